@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AdminHandler returns an http.Handler which allows loggers to be inspected and controlled at runtime, i.e. to enable
+// debug logging on a live service without redeploying it. It is not registered anywhere by default and performs no
+// authentication, so it should only be served on an internal address:
+//
+//	http.Handle("/logger/", http.StripPrefix("/logger", logger.AdminHandler()))
+//
+// The following endpoints are provided, where loggers are selected by one or more category or id query parameters.
+// Categories are matched in the same way as SetEnabledByCategory, so they are case sensitive and may be glob patterns.
+//
+//	GET  /loggers                                list all loggers
+//	POST /loggers/enable?category=INFO           enable the selected loggers
+//	POST /loggers/disable?id=3                   disable the selected loggers
+//	POST /loggers/level?category=INFO&level=warn set the Level of the selected loggers
+//	POST /buffered?enabled=true                  enable or disable buffered logging
+//	GET  /stats                                  logger counts and queue statistics
+func AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/loggers", adminMethod(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, Stats().Loggers)
+	}))
+	mux.HandleFunc("/loggers/enable", adminMethod(http.MethodPost, adminSetEnabled(true)))
+	mux.HandleFunc("/loggers/disable", adminMethod(http.MethodPost, adminSetEnabled(false)))
+	mux.HandleFunc("/loggers/level", adminMethod(http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+		lvl, ok := ParseLevel(r.FormValue("level"))
+		if !ok {
+			http.Error(w, "invalid level", http.StatusBadRequest)
+			return
+		}
+		selected, err := selectLoggers(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, l := range selected {
+			l.SetLevel(lvl)
+		}
+		writeAdminJSON(w, statsOf(selected))
+	}))
+	mux.HandleFunc("/buffered", adminMethod(http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+		enabled, err := strconv.ParseBool(r.FormValue("enabled"))
+		if err != nil {
+			http.Error(w, "invalid enabled value", http.StatusBadRequest)
+			return
+		}
+		SetBuffered(enabled)
+		writeAdminJSON(w, Stats().Queue)
+	}))
+	mux.HandleFunc("/stats", adminMethod(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, Stats())
+	}))
+	return mux
+}
+
+// adminSetEnabled returns a handler which enables or disables the selected loggers.
+func adminSetEnabled(enabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		selected, err := selectLoggers(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, l := range selected {
+			l.SetEnabled(enabled)
+		}
+		writeAdminJSON(w, statsOf(selected))
+	}
+}
+
+// adminMethod rejects requests which do not use the provided method.
+func adminMethod(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// selectLoggers returns the loggers matching the category and id parameters of a request. At least one parameter must
+// be provided.
+func selectLoggers(r *http.Request) ([]*Logger, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	categories := r.Form["category"]
+	ids := make(map[int]bool)
+	for _, id := range r.Form["id"] {
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, errors.New("invalid id: " + id)
+		}
+		ids[n] = true
+	}
+	return matchLoggers(categories, ids)
+}
+
+// matchLoggers returns the loggers with one of the provided category names or IDs. Categories are matched with
+// matchCategory, as for SetEnabledByCategory. At least one category or ID must be provided.
+func matchLoggers(categories []string, ids map[int]bool) ([]*Logger, error) {
+	if len(categories) == 0 && len(ids) == 0 {
+		return nil, errors.New("a category or id must be provided")
+	}
+
+	var selected []*Logger
+	for _, l := range registeredLoggers() {
+		if ids[l.id] {
+			selected = append(selected, l)
+			continue
+		}
+		for _, c := range categories {
+			if matchCategory(c, l.Category.Name) {
+				selected = append(selected, l)
+				break
+			}
+		}
+	}
+	return selected, nil
+}
+
+// writeAdminJSON writes v as the JSON response body.
+func writeAdminJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// containsString reports whether list contains s, ignoring case.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAdminMatchesCategoriesLikeSetEnabledByCategory(t *testing.T) {
+	upper := NewLogger(ioutil.Discard, "ADMIN_UPPER", true)
+	lower := NewLogger(ioutil.Discard, "admin_lower", true)
+	defer RemoveLogger(upper, lower)
+
+	handler := AdminHandler()
+	request := httptest.NewRequest(http.MethodPost, "/loggers/disable?category=ADMIN_*", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	if upper.IsEnabled() || !lower.IsEnabled() {
+		t.Fatalf("expected only ADMIN_UPPER to be disabled, got %v and %v", upper.IsEnabled(), lower.IsEnabled())
+	}
+
+	SetEnabledByCategory(true, "ADMIN_*")
+	if !upper.IsEnabled() || !lower.IsEnabled() {
+		t.Fatal("expected SetEnabledByCategory to match the same loggers")
+	}
+}
+
+func TestAdminConcurrentWithLogging(t *testing.T) {
+	l := NewLogger(ioutil.Discard, "ADMIN_RACE", true)
+	defer RemoveLogger(l)
+	handler := AdminHandler()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.Log("message")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			for _, path := range []string{"/loggers/disable", "/loggers/enable", "/loggers/level"} {
+				request := httptest.NewRequest(http.MethodPost, path+"?category=ADMIN_RACE&level=warn", nil)
+				handler.ServeHTTP(httptest.NewRecorder(), request)
+			}
+		}
+	}()
+	wg.Wait()
+	Flush(time.Second)
+}
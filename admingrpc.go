@@ -0,0 +1,228 @@
+package logger
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// adminGRPCService is the fully qualified name of the AdminService defined in proto/admin.proto.
+const adminGRPCService = "/logger.admin.v1.AdminService/"
+
+// gRPC status codes returned by the admin service.
+const (
+	grpcOK              = 0
+	grpcInvalidArgument = 3
+	grpcInternal        = 13
+	grpcUnimplemented   = 12
+)
+
+// maxAdminGRPCMessage is the largest request message accepted by the admin service.
+const maxAdminGRPCMessage = 1 << 20
+
+// AdminGRPCHandler returns an http.Handler serving the gRPC AdminService defined in proto/admin.proto, which mirrors
+// the HTTP admin API so that orchestration tooling can control logging across a fleet. As with the OTLPSink, messages
+// are encoded by hand rather than depending on the gRPC and protobuf libraries. gRPC requires HTTP/2, so the handler
+// must be served over TLS or with unencrypted HTTP/2 enabled, as by NewAdminGRPCServer. Like AdminHandler, it performs
+// no authentication.
+func AdminGRPCHandler() http.Handler {
+	return http.HandlerFunc(serveAdminGRPC)
+}
+
+// NewAdminGRPCServer creates an http.Server which serves the gRPC AdminService on addr over unencrypted HTTP/2:
+//
+//	go logger.NewAdminGRPCServer("localhost:9091").ListenAndServe()
+func NewAdminGRPCServer(addr string) *http.Server {
+	var protocols http.Protocols
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Server{Addr: addr, Handler: AdminGRPCHandler(), Protocols: &protocols}
+}
+
+// serveAdminGRPC handles a single unary AdminService call.
+func serveAdminGRPC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "unsupported media type", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+	request, err := readGRPCMessage(r.Body)
+	if err != nil {
+		writeGRPCStatus(w, grpcInvalidArgument, err.Error())
+		return
+	}
+
+	var response []byte
+	switch strings.TrimPrefix(r.URL.Path, adminGRPCService) {
+	case "ListLoggers":
+		response = encodeAdminLoggers(statsOf(registeredLoggers()))
+	case "GetStats":
+		stats := Stats()
+		response = encodeAdminLoggers(stats.Loggers)
+		response = protoAppendBytes(response, 2, encodeAdminQueue(stats.Queue))
+	case "SetEnabled":
+		response, err = adminGRPCSetEnabled(request)
+	case "SetLevel":
+		response, err = adminGRPCSetLevel(request)
+	default:
+		writeGRPCStatus(w, grpcUnimplemented, "unknown method "+r.URL.Path)
+		return
+	}
+	if err != nil {
+		writeGRPCStatus(w, grpcInvalidArgument, err.Error())
+		return
+	}
+
+	frame := make([]byte, 5, 5+len(response))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(response)))
+	if _, err := w.Write(append(frame, response...)); err != nil {
+		writeGRPCStatus(w, grpcInternal, err.Error())
+		return
+	}
+	writeGRPCStatus(w, grpcOK, "")
+}
+
+// adminGRPCSetEnabled handles a SetEnabledRequest.
+func adminGRPCSetEnabled(request []byte) ([]byte, error) {
+	var selector []byte
+	var enabled bool
+	err := protoDecode(request, func(field, wireType int, v uint64, data []byte) error {
+		switch field {
+		case 1:
+			selector = data
+		case 2:
+			enabled = v != 0
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := decodeAdminSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range selected {
+		l.SetEnabled(enabled)
+	}
+	return encodeAdminLoggers(statsOf(selected)), nil
+}
+
+// adminGRPCSetLevel handles a SetLevelRequest.
+func adminGRPCSetLevel(request []byte) ([]byte, error) {
+	var selector []byte
+	var name string
+	err := protoDecode(request, func(field, wireType int, v uint64, data []byte) error {
+		switch field {
+		case 1:
+			selector = data
+		case 2:
+			name = string(data)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lvl, ok := ParseLevel(name)
+	if !ok {
+		return nil, errors.New("invalid level: " + name)
+	}
+	selected, err := decodeAdminSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range selected {
+		l.SetLevel(lvl)
+	}
+	return encodeAdminLoggers(statsOf(selected)), nil
+}
+
+// decodeAdminSelector decodes a Selector and returns the loggers it matches.
+func decodeAdminSelector(b []byte) ([]*Logger, error) {
+	var categories []string
+	ids := make(map[int]bool)
+	err := protoDecode(b, func(field, wireType int, v uint64, data []byte) error {
+		switch field {
+		case 1:
+			categories = append(categories, string(data))
+		case 2:
+			values, err := protoDecodeVarints(wireType, v, data)
+			if err != nil {
+				return err
+			}
+			for _, id := range values {
+				ids[int(int64(id))] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matchLoggers(categories, ids)
+}
+
+// encodeAdminLoggers encodes a message with the provided loggers as repeated LoggerInfo field 1, which is shared by
+// each of the AdminService responses.
+func encodeAdminLoggers(list []LoggerStats) []byte {
+	var b []byte
+	for _, l := range list {
+		var info []byte
+		info = protoAppendUint(info, 1, uint64(l.ID))
+		info = protoAppendString(info, 2, l.Category)
+		info = protoAppendString(info, 3, l.Level)
+		info = protoAppendBool(info, 4, l.Enabled)
+		info = protoAppendUint(info, 5, uint64(l.Count))
+		info = protoAppendUint(info, 6, uint64(l.Dropped))
+		// empty messages must still be written to preserve their position in the list
+		b = protoAppendTag(b, 1, protoBytes)
+		b = protoAppendVarint(b, uint64(len(info)))
+		b = append(b, info...)
+	}
+	return b
+}
+
+// encodeAdminQueue encodes a QueueStats message.
+func encodeAdminQueue(q QueueStats) []byte {
+	var b []byte
+	b = protoAppendBool(b, 1, q.Buffered)
+	b = protoAppendUint(b, 2, uint64(q.Depth))
+	b = protoAppendUint(b, 3, uint64(q.Capacity))
+	return protoAppendUint(b, 4, q.Writes)
+}
+
+// readGRPCMessage reads a single length-prefixed gRPC message frame.
+func readGRPCMessage(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, errors.New("failed to read request message: " + err.Error())
+	}
+	if header[0] != 0 {
+		return nil, errors.New("compressed requests are not supported")
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > maxAdminGRPCMessage {
+		return nil, errors.New("request message too large")
+	}
+	message := make([]byte, length)
+	if _, err := io.ReadFull(r, message); err != nil {
+		return nil, errors.New("failed to read request message: " + err.Error())
+	}
+	return message, nil
+}
+
+// writeGRPCStatus sets the gRPC status trailers of a response.
+func writeGRPCStatus(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	if message != "" {
+		w.Header().Set("Grpc-Message", message)
+	}
+}
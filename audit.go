@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditMACPrefix separates each audit line from the HMAC which follows it.
+const auditMACPrefix = " hmac="
+
+// AuditSink is a Sink which writes tamper-evident audit logs. Each line is followed by an HMAC-SHA256 computed over the
+// previous line's HMAC and the line itself, so modifying, inserting, removing or reordering any line other than the last
+// invalidates every HMAC which follows it. Logs written by an AuditSink can be checked with VerifyAudit. Lines removed
+// from the end of a log leave a valid chain, so truncation can only be detected by keeping the HMAC of the last line
+// elsewhere, i.e. as returned by LastAuditMAC, and comparing it with that of the log.
+type AuditSink struct {
+	mu       sync.Mutex
+	w        io.Writer
+	key      []byte
+	previous []byte
+}
+
+// NewAuditSink creates an AuditSink which writes to w, signing each line with key. The sink starts a new chain, so it
+// must not be used to append to an existing audit log; use NewAuditSinkFrom instead.
+func NewAuditSink(w io.Writer, key []byte) *AuditSink {
+	return &AuditSink{w: w, key: key}
+}
+
+// NewAuditSinkFrom creates an AuditSink which continues the chain of an existing audit log, i.e. to append to the log
+// after a restart. lastMAC is the HMAC of the log's last line, as returned by LastAuditMAC, so that the log still
+// passes VerifyAudit once lines have been appended. A nil lastMAC starts a new chain, as for an empty log.
+func NewAuditSinkFrom(w io.Writer, key, lastMAC []byte) *AuditSink {
+	return &AuditSink{w: w, key: key, previous: lastMAC}
+}
+
+// WriteEntry writes the entry as a single line followed by its chained HMAC. Newlines within the message are quoted so
+// that each entry occupies exactly one line.
+func (s *AuditSink) WriteEntry(e Entry) error {
+	line := e.Time.UTC().Format(time.RFC3339Nano) + " " + e.Level.String()
+	if e.Category != "" {
+		line += " [" + e.Category + "]"
+	}
+	message := e.Message
+	if strings.ContainsAny(message, "\r\n") {
+		message = strconv.Quote(message)
+	}
+	line += " " + message
+	if len(e.Fields) > 0 {
+		line += " " + composeFields(e.Fields)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mac := auditMAC(s.key, s.previous, line)
+	if _, err := io.WriteString(s.w, line+auditMACPrefix+hex.EncodeToString(mac)+"\n"); err != nil {
+		return err
+	}
+	s.previous = mac
+	return nil
+}
+
+// Write implements io.Writer so that an AuditSink can be used as a Logger's Writer. Raw writes (which have no
+// structure) are written as LevelInfo entries without a category.
+func (s *AuditSink) Write(p []byte) (int, error) {
+	message := bytes.TrimSpace(p)
+	if len(message) == 0 {
+		return len(p), nil
+	}
+	e := Entry{
+		Time:    now(),
+		Level:   LevelInfo,
+		Message: string(message),
+	}
+	if err := s.WriteEntry(e); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// VerifyAudit reads an audit log written by an AuditSink and checks the HMAC chain using key. An error identifying the
+// first line which fails verification is returned if the log has been tampered with. Blank lines are ignored, as by
+// LastAuditMAC. Lines removed from the end of the log are not detected, as described by AuditSink.
+func VerifyAudit(r io.Reader, key []byte) error {
+	var previous []byte
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		text := scanner.Text()
+		if text == "" {
+			continue
+		}
+		i := strings.LastIndex(text, auditMACPrefix)
+		if i < 0 {
+			return fmt.Errorf("audit: line %d has no hmac", lineNo)
+		}
+		mac, err := hex.DecodeString(text[i+len(auditMACPrefix):])
+		if err != nil {
+			return fmt.Errorf("audit: line %d has a malformed hmac: %s", lineNo, err)
+		}
+		if !hmac.Equal(mac, auditMAC(key, previous, text[:i])) {
+			return fmt.Errorf("audit: line %d failed verification", lineNo)
+		}
+		previous = mac
+	}
+	return scanner.Err()
+}
+
+// LastAuditMAC reads an audit log written by an AuditSink and returns the HMAC of its last line, to continue the chain
+// with NewAuditSinkFrom. nil is returned for an empty log. Blank lines are ignored, and the chain is not verified.
+func LastAuditMAC(r io.Reader) ([]byte, error) {
+	var last string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if text := scanner.Text(); text != "" {
+			last = text
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if last == "" {
+		return nil, nil
+	}
+
+	i := strings.LastIndex(last, auditMACPrefix)
+	if i < 0 {
+		return nil, fmt.Errorf("audit: last line has no hmac")
+	}
+	mac, err := hex.DecodeString(last[i+len(auditMACPrefix):])
+	if err != nil {
+		return nil, fmt.Errorf("audit: last line has a malformed hmac: %s", err)
+	}
+	return mac, nil
+}
+
+// auditMAC computes the HMAC of a line chained to the previous line's HMAC.
+func auditMAC(key, previous []byte, line string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(previous)
+	io.WriteString(h, line)
+	return h.Sum(nil)
+}
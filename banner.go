@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Banner logs a multi-line startup banner with the Logger, made up of the application's name and version, the build
+// information embedded in the binary (Go version, main module path, VCS revision and commit time where available) and
+// then the provided fields as a summary of the application's configuration, i.e.
+//
+//	[LOG] 10/15 09:30:00 ==== myapp v1.4.2 ====
+//	                     go       go1.22.1
+//	                     module   github.com/me/myapp
+//	                     revision 3f2a9c1e7b40 (modified)
+//	                     built    2026-10-14T16:02:11Z
+//	                     port     8080
+//
+// The banner is logged as a single message, so it is composed with the Logger's Category and Timestamp components and
+// continuation lines are aligned under the Message component when multi-line indentation is enabled. An empty version
+// is omitted.
+func Banner(l *Logger, appName, version string, fields ...Field) {
+	rows := append(buildInfoFields(), fields...)
+
+	width := 0
+	for _, f := range rows {
+		if w := textWidth(f.Key); w > width {
+			width = w
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("==== ")
+	b.WriteString(appName)
+	if version != "" {
+		b.WriteByte(' ')
+		b.WriteString(version)
+	}
+	b.WriteString(" ====")
+	for _, f := range rows {
+		b.WriteByte('\n')
+		b.WriteString(f.Key)
+		b.WriteString(strings.Repeat(" ", width-textWidth(f.Key)+1))
+		b.WriteString(f.String())
+	}
+	l.performLog(b.String(), false, nil)
+}
+
+// buildInfoFields returns the build information embedded in the binary as Fields, or none if it is unavailable.
+func buildInfoFields() []Field {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	fields := []Field{String("go", info.GoVersion)}
+	if info.Main.Path != "" {
+		fields = append(fields, String("module", info.Main.Path))
+	}
+	var revision, built string
+	modified := false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.time":
+			built = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision != "" {
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		if modified {
+			revision += " (modified)"
+		}
+		fields = append(fields, String("revision", revision))
+	}
+	if built != "" {
+		fields = append(fields, String("built", built))
+	}
+	return fields
+}
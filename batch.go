@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultFlushInterval is used by batching sinks which have not been given a FlushInterval.
+const defaultFlushInterval = 5 * time.Second
+
+// maxQueuedBatches is the number of full batches which can be waiting to be sent before batching sinks reject entries
+// with ErrBatchFull, bounding the memory used while a remote service is slow.
+const maxQueuedBatches = 4
+
+// ErrBatchFull is returned by batching sinks, i.e. DatadogSink and OTLPSink, when an entry cannot be queued because
+// the background sender has fallen too many batches behind. The entry is then handled by the Logger's ErrorPolicy.
+var ErrBatchFull = errors.New("batch full")
+
+// errBatcherClosed is returned for entries written to a batching sink after it has been closed.
+var errBatcherClosed = errors.New("sink is closed")
+
+// batcher queues entries for sinks which send them to remote services in batches. Batches are passed to send from a
+// background goroutine when the batch size is reached or the flush interval elapses on the Clock, so the log poller is
+// never blocked by the remote service. Batches which fail to be sent in the background are reported by the next call
+// to add or close.
+type batcher struct {
+	send func([]Entry) error
+
+	mu     sync.Mutex
+	batch  []Entry
+	size   int
+	failed error
+	closed bool
+
+	startOnce  sync.Once
+	closeOnce  sync.Once
+	stopTicker func()
+	flushCh    chan struct{}
+	exitCh     chan struct{}
+	doneCh     chan struct{}
+}
+
+// newBatcher creates a batcher which passes batches to send.
+func newBatcher(send func([]Entry) error) *batcher {
+	return &batcher{
+		send:    send,
+		flushCh: make(chan struct{}, 1),
+		exitCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// start starts the background sender if it is not already running.
+func (b *batcher) start(interval time.Duration) {
+	b.startOnce.Do(func() {
+		if interval <= 0 {
+			interval = defaultFlushInterval
+		}
+		b.stopTicker = every(interval, b.signal)
+		go b.run()
+	})
+}
+
+func (b *batcher) run() {
+	defer close(b.doneCh)
+	defer b.stopTicker()
+
+	for {
+		select {
+		case <-b.flushCh:
+			b.report(b.flush())
+		case <-b.exitCh:
+			b.report(b.flush())
+			return
+		}
+	}
+}
+
+// signal wakes the background sender to flush the queued entries.
+func (b *batcher) signal() {
+	select {
+	case b.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+// report records the error of a batch sent in the background, to be returned by the next call to add or close.
+func (b *batcher) report(err error) {
+	if err == nil {
+		return
+	}
+	b.mu.Lock()
+	b.failed = err
+	b.mu.Unlock()
+}
+
+// add queues an entry, triggering a flush once size entries have been queued. The background sender is started with
+// the provided flush interval by the first entry added. If a batch has failed to be sent since the previous call, its
+// error is returned and the entry is not queued, so that the failure reaches the Logger's ErrorPolicy and any wrapping
+// sinks such as RetrySink and BreakerSink. Entries are rejected with ErrBatchFull once maxQueuedBatches batches are
+// waiting to be sent.
+func (b *batcher) add(e Entry, size int, interval time.Duration) error {
+	b.start(interval)
+	if size < 1 {
+		size = 1
+	}
+
+	b.mu.Lock()
+	if err := b.failed; err != nil {
+		b.failed = nil
+		b.mu.Unlock()
+		return err
+	}
+	if b.closed {
+		b.mu.Unlock()
+		return errBatcherClosed
+	}
+	if len(b.batch) >= size*maxQueuedBatches {
+		b.mu.Unlock()
+		return ErrBatchFull
+	}
+	b.batch = append(b.batch, e)
+	b.size = size
+	full := len(b.batch) >= size
+	b.mu.Unlock()
+
+	if full {
+		b.signal()
+	}
+	return nil
+}
+
+// flush sends any queued entries immediately, in batches of at most the size passed to add. The error of the last
+// batch which failed is returned.
+func (b *batcher) flush() error {
+	b.mu.Lock()
+	batch, size := b.batch, b.size
+	b.batch = nil
+	b.mu.Unlock()
+
+	var err error
+	for len(batch) > 0 {
+		n := len(batch)
+		if size > 0 && n > size {
+			n = size
+		}
+		if sendErr := b.send(batch[:n]); sendErr != nil {
+			err = sendErr
+		}
+		batch = batch[n:]
+	}
+	return err
+}
+
+// close stops the background sender once any queued entries have been sent, returning the error of the final flush or
+// of an earlier batch which failed and has not been reported. Entries added afterwards are rejected. Only the first
+// call stops the sender, and later calls return nil.
+func (b *batcher) close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		b.start(0)
+		b.mu.Lock()
+		b.closed = true
+		b.mu.Unlock()
+		close(b.exitCh)
+		<-b.doneCh
+
+		b.mu.Lock()
+		err, b.failed = b.failed, nil
+		b.mu.Unlock()
+	})
+	return err
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingSender signals each batch passed to it, failing with err if it is set.
+type recordingSender struct {
+	err  error
+	sent chan struct{}
+}
+
+func newRecordingSender() *recordingSender {
+	return &recordingSender{sent: make(chan struct{}, 100)}
+}
+
+func (s *recordingSender) send(batch []Entry) error {
+	s.sent <- struct{}{}
+	return s.err
+}
+
+func (s *recordingSender) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.sent:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a batch to be sent")
+	}
+}
+
+func TestBatcherFlushesOnClock(t *testing.T) {
+	c := NewManualClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	SetClock(c)
+	defer SetClock(nil)
+
+	sender := newRecordingSender()
+	b := newBatcher(sender.send)
+	defer b.close()
+	if err := b.add(Entry{Message: "queued"}, 10, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// the batch is only sent once the interval has elapsed on the Clock
+	c.Add(30 * time.Second)
+	select {
+	case <-sender.sent:
+		t.Fatal("batch sent before the flush interval elapsed")
+	case <-time.After(20 * time.Millisecond):
+	}
+	c.Add(30 * time.Second)
+	sender.wait(t)
+}
+
+func TestBatcherReportsBackgroundFailures(t *testing.T) {
+	sender := newRecordingSender()
+	sender.err = errors.New("unavailable")
+	b := newBatcher(sender.send)
+
+	if err := b.add(Entry{Message: "first"}, 1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	sender.wait(t)
+
+	// the failure is returned by the next add, which does not queue its entry
+	var err error
+	for deadline := time.Now().Add(time.Second); err == nil && time.Now().Before(deadline); {
+		err = b.add(Entry{Message: "rejected"}, 100, time.Hour)
+		time.Sleep(time.Millisecond)
+	}
+	if err != sender.err {
+		t.Fatalf("expected the send error to be returned, got %v", err)
+	}
+
+	// the final flush error is returned by close, and closing again is harmless
+	if err := b.add(Entry{Message: "last"}, 100, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.close(); err != sender.err {
+		t.Fatalf("expected close to return the final send error, got %v", err)
+	}
+	if err := b.close(); err != nil {
+		t.Fatalf("expected a second close to return nil, got %v", err)
+	}
+	if err := b.add(Entry{Message: "closed"}, 100, time.Hour); err != errBatcherClosed {
+		t.Fatalf("expected entries to be rejected once closed, got %v", err)
+	}
+}
+
+func TestBatcherRejectsEntriesWhenFull(t *testing.T) {
+	sending := make(chan struct{}, 100)
+	release := make(chan struct{})
+	var sent int32
+	b := newBatcher(func(batch []Entry) error {
+		sending <- struct{}{}
+		<-release
+		atomic.AddInt32(&sent, int32(len(batch)))
+		return nil
+	})
+
+	// the first batch blocks the sender, so entries back up until maxQueuedBatches batches are waiting
+	const size = 2
+	for i := 0; i < size; i++ {
+		if err := b.add(Entry{}, size, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-sending
+	for i := 0; i < size*maxQueuedBatches; i++ {
+		if err := b.add(Entry{}, size, time.Hour); err != nil {
+			t.Fatalf("entry %d was rejected: %s", i, err)
+		}
+	}
+	if err := b.add(Entry{}, size, time.Hour); err != ErrBatchFull {
+		t.Fatalf("expected ErrBatchFull, got %v", err)
+	}
+
+	close(release)
+	if err := b.close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&sent); n != size*(maxQueuedBatches+1) {
+		t.Errorf("expected %d entries to be sent, got %d", size*(maxQueuedBatches+1), n)
+	}
+}
+
+func TestDatadogSinkReportsWriteErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	sink := NewDatadogSink("key")
+	sink.URL = server.URL
+	sink.BatchSize = 1
+
+	var reported int32
+	OnWriteError(func(l *Logger, err error) {
+		atomic.AddInt32(&reported, 1)
+	})
+	defer OnWriteError(nil)
+
+	l := NewLogger(sink, "DATADOG", true)
+	l.SetErrorPolicy(ErrorDrop)
+	defer RemoveLogger(l)
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&reported) == 0 && time.Now().Before(deadline); {
+		l.Log("rejected by the intake")
+		Flush(time.Second)
+	}
+	if atomic.LoadInt32(&reported) == 0 {
+		t.Fatal("expected the failed batch to be reported to the write error handler")
+	}
+	sink.Close()
+}
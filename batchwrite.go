@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultWriteBatchInterval is the longest a batched message waits to be written if no interval is provided.
+const defaultWriteBatchInterval = time.Second
+
+var (
+	writeBatchMu       sync.Mutex
+	writeBatchSize     int
+	writeBatchInterval = defaultWriteBatchInterval
+)
+
+// SetWriteBatching coalesces the messages queued for each Writer into a single Write call, reducing the number of
+// syscalls made when logging at a high rate to files. A batch is written once it reaches size bytes or once interval
+// has elapsed since its first message, whichever comes first. Batches are also written when Flush is called and when
+// the poller stops. A size of 0 disables batching, which is the default. Sinks and MultiSinks are not batched, as they
+// receive each Entry individually, and neither are Writers which cannot be used as map keys, as they share a queue.
+func SetWriteBatching(size int, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultWriteBatchInterval
+	}
+	writeBatchMu.Lock()
+	writeBatchSize = size
+	writeBatchInterval = interval
+	writeBatchMu.Unlock()
+}
+
+// batchingEnabled returns the current batch size and interval, where a size of 0 means batching is disabled.
+func batchingEnabled() (int, time.Duration) {
+	writeBatchMu.Lock()
+	defer writeBatchMu.Unlock()
+	return writeBatchSize, writeBatchInterval
+}
+
+// batchable reports whether messages written to w can be coalesced into a batch.
+func batchable(w io.Writer) bool {
+	switch w.(type) {
+	case nil, Sink, *MultiSink:
+		return false
+	}
+	return true
+}
+
+// writeBatch buffers the composed messages of a single Writer. It is only used by the writer goroutine of that Writer.
+type writeBatch struct {
+	writer io.Writer
+	buf    bytes.Buffer
+	// logger is the Logger of the most recent message, whose ErrorPolicy is applied if the batch fails to be written.
+	logger *Logger
+	timer  *time.Timer
+}
+
+// Write appends a composed message to the batch. The batch is written once it reaches size bytes.
+func (b *writeBatch) Write(p []byte) (int, error) {
+	size, interval := batchingEnabled()
+	if b.buf.Len() == 0 {
+		if b.timer == nil {
+			b.timer = time.NewTimer(interval)
+		} else {
+			b.timer.Reset(interval)
+		}
+	}
+	b.buf.Write(p)
+	if b.buf.Len() >= size {
+		b.flush()
+	}
+	return len(p), nil
+}
+
+// flush writes the batch to the Writer in a single call. Write errors are reported using the Logger of the most recent
+// message, with the whole batch being written to its fallback writer.
+func (b *writeBatch) flush() {
+	if b.buf.Len() == 0 {
+		return
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	batch := b.buf.Bytes()
+	err := b.logger.retryWrite(func() error {
+		_, err := b.writer.Write(batch)
+		return err
+	})
+	if err != nil {
+		b.logger.handleWriteError(err, b.writer, func() string {
+			return strings.TrimSuffix(string(batch), "\n")
+		})
+	}
+	b.buf.Reset()
+}
+
+// expired returns a channel which receives once the batch interval has elapsed, or nil if the batch is empty.
+func (b *writeBatch) expired() <-chan time.Time {
+	if b == nil || b.buf.Len() == 0 {
+		return nil
+	}
+	return b.timer.C
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+// benchmarkLogger creates a Logger which writes to ioutil.Discard through the buffered queue, removing it and waiting
+// for its messages to be written once the benchmark ends.
+func benchmarkLogger(b *testing.B) *Logger {
+	SetBuffered(true)
+	l := NewLogger(ioutil.Discard, "BENCH", true)
+	b.Cleanup(func() {
+		Flush(10 * time.Second)
+		RemoveLogger(l)
+		SetBuffered(false)
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	return l
+}
+
+func BenchmarkLogFields(b *testing.B) {
+	l := benchmarkLogger(b)
+	for i := 0; i < b.N; i++ {
+		l.LogFields("request handled", Int("status", 200), Duration("elapsed", time.Millisecond), Bool("cached", true))
+	}
+}
+
+func BenchmarkLog(b *testing.B) {
+	l := benchmarkLogger(b)
+	for i := 0; i < b.N; i++ {
+		l.Log("request handled")
+	}
+}
+
+func BenchmarkLogf(b *testing.B) {
+	l := benchmarkLogger(b)
+	for i := 0; i < b.N; i++ {
+		l.Logf("request %d handled in %s", i, time.Millisecond)
+	}
+}
+
+func BenchmarkComposeLine(b *testing.B) {
+	l := NewLogger(ioutil.Discard, "BENCH", true)
+	defer RemoveLogger(l)
+	item := queueItem{
+		logger:    l,
+		writer:    l.Writer,
+		category:  l.Category,
+		timestamp: l.Timestamp,
+		entry:     l.newEntry("request handled", []Field{Int("status", 200), Duration("elapsed", time.Millisecond)}),
+		order:     l.Order,
+	}
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			line := getBuffer()
+			composeLine(line, item, "")
+			putBuffer(line)
+		}
+	})
+	b.Run("unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			composeLine(new(bytes.Buffer), item, "")
+		}
+	})
+}
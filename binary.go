@@ -0,0 +1,348 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+// binaryFileMagic is written at the start of every binary log file to identify its format. Files written before
+// Levels were encoded as varints start with binaryFileMagicV1, and store each Level as a single byte.
+const (
+	binaryFileMagic   = "LOGBIN2\n"
+	binaryFileMagicV1 = "LOGBIN1\n"
+)
+
+// maxBinaryRecord is the largest record DecodeBinaryLog will accept, protecting it from corrupt length prefixes.
+const maxBinaryRecord = 64 * 1024 * 1024
+
+// binary log record types.
+const (
+	binaryCategoryRecord byte = iota
+	binaryEntryRecord
+)
+
+// BinaryFile is a Sink which writes entries to a file in a compact binary format, for logging at the highest rates
+// without the cost of composing text. Each record is prefixed with its length as a uvarint. The first entry of each
+// Category is preceded by a record which assigns the Category Name an ID, so that entries only refer to their Category
+// by ID. Entries contain the sequence number, timestamp, Level, Message and typed Fields. Files written by a BinaryFile
+// can be read with DecodeBinaryLog, or with the "decode" command of cmd/logger.
+type BinaryFile struct {
+	mu         sync.Mutex
+	path       string
+	file       *os.File
+	categories map[string]uint64
+	buf        []byte
+}
+
+// OpenBinaryFile opens (or creates) the file at path for appending binary logs.
+func OpenBinaryFile(path string) (*BinaryFile, error) {
+	file, err := openBinaryFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BinaryFile{path: path, file: file, categories: make(map[string]uint64)}, nil
+}
+
+// openBinaryFile opens path for appending, writing the format header if the file is new. Existing files must have been
+// written in the current format, as records of different versions cannot be mixed in one file.
+func openBinaryFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size() == 0 {
+		if _, err := io.WriteString(file, binaryFileMagic); err != nil {
+			file.Close()
+			return nil, err
+		}
+		return file, nil
+	}
+
+	magic := make([]byte, len(binaryFileMagic))
+	if _, err := file.ReadAt(magic, 0); err != nil || string(magic) != binaryFileMagic {
+		file.Close()
+		return nil, fmt.Errorf("%s is not a binary log of the current version", path)
+	}
+	return file, nil
+}
+
+// WriteEntry appends the entry to the file as a single write, along with the definition of its Category if it has not
+// been written to the file before.
+func (f *BinaryFile) WriteEntry(e Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.buf = f.buf[:0]
+	var categoryID uint64
+	if e.Category != "" {
+		id, ok := f.categories[e.Category]
+		if !ok {
+			// IDs start at 1, leaving 0 for entries without a Category
+			id = uint64(len(f.categories) + 1)
+			record := append([]byte{binaryCategoryRecord}, binary.AppendUvarint(nil, id)...)
+			record = appendBinaryString(record, e.Category)
+			f.buf = appendBinaryRecord(f.buf, record)
+		}
+		categoryID = id
+	}
+
+	record := []byte{binaryEntryRecord}
+	record = binary.AppendUvarint(record, categoryID)
+	record = binary.AppendUvarint(record, e.Sequence)
+	record = binary.AppendVarint(record, e.Time.UnixNano())
+	record = binary.AppendVarint(record, int64(e.Level))
+	record = appendBinaryString(record, e.Message)
+	record = binary.AppendUvarint(record, uint64(len(e.Fields)))
+	for _, field := range e.Fields {
+		record = appendBinaryField(record, field)
+	}
+	f.buf = appendBinaryRecord(f.buf, record)
+
+	if _, err := f.file.Write(f.buf); err != nil {
+		return err
+	}
+	if categoryID != 0 {
+		f.categories[e.Category] = categoryID
+	}
+	return nil
+}
+
+// Write implements io.Writer so that a BinaryFile can be used as a Logger's Writer. Raw writes are stored as LevelInfo
+// entries without a category.
+func (f *BinaryFile) Write(p []byte) (int, error) {
+	message := bytes.TrimSpace(p)
+	if len(message) == 0 {
+		return len(p), nil
+	}
+	if err := f.WriteEntry(Entry{Time: now(), Level: LevelInfo, Message: string(message)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Reopen closes the file and opens its path again, so that writing continues to a new file once the old one has been
+// moved aside by log rotation. Category definitions are written again to the new file as they are used.
+func (f *BinaryFile) Reopen() error {
+	file, err := openBinaryFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	old := f.file
+	f.file = file
+	f.categories = make(map[string]uint64)
+	f.mu.Unlock()
+	return old.Close()
+}
+
+// Close closes the underlying file.
+func (f *BinaryFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// appendBinaryRecord appends a record to dst, prefixed with its length.
+func appendBinaryRecord(dst, record []byte) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(record)))
+	return append(dst, record...)
+}
+
+// appendBinaryString appends s to dst, prefixed with its length.
+func appendBinaryString(dst []byte, s string) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
+
+// appendBinaryField appends the key, kind and value of a Field to dst. Any Fields are stored as their text.
+func appendBinaryField(dst []byte, f Field) []byte {
+	dst = appendBinaryString(dst, f.Key)
+	switch f.kind {
+	case fieldInt, fieldDuration:
+		dst = append(dst, byte(f.kind))
+		return binary.AppendVarint(dst, int64(f.num))
+	case fieldUint, fieldBool:
+		dst = append(dst, byte(f.kind))
+		return binary.AppendUvarint(dst, f.num)
+	case fieldFloat:
+		dst = append(dst, byte(f.kind))
+		return binary.LittleEndian.AppendUint64(dst, f.num)
+	}
+	dst = append(dst, byte(fieldString))
+	return appendBinaryString(dst, f.String())
+}
+
+// DecodeBinaryLog reads a binary log written by a BinaryFile from r and writes each entry to w using the provided
+// encoder, i.e. TextEncoder or JSONEncoder. Files written by earlier versions of BinaryFile are also accepted.
+func DecodeBinaryLog(w io.Writer, r io.Reader, encoder EncoderFunc) error {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(binaryFileMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return errors.New("not a binary log file")
+	}
+	byteLevels := string(magic) == binaryFileMagicV1
+	if string(magic) != binaryFileMagic && !byteLevels {
+		return errors.New("not a binary log file")
+	}
+
+	categories := make(map[uint64]string)
+	for recordNo := 1; ; recordNo++ {
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read record %d: %s", recordNo, err)
+		}
+		if n == 0 || n > maxBinaryRecord {
+			return fmt.Errorf("record %d has an invalid length of %d", recordNo, n)
+		}
+		record := make([]byte, n)
+		if _, err := io.ReadFull(br, record); err != nil {
+			return fmt.Errorf("failed to read record %d: %s", recordNo, err)
+		}
+
+		d := binaryDecoder{data: record[1:], byteLevels: byteLevels}
+		switch record[0] {
+		case binaryCategoryRecord:
+			id := d.uvarint()
+			categories[id] = d.string()
+			if d.err != nil {
+				return fmt.Errorf("failed to decode record %d: %s", recordNo, d.err)
+			}
+
+		case binaryEntryRecord:
+			e := d.entry(categories)
+			if d.err != nil {
+				return fmt.Errorf("failed to decode record %d: %s", recordNo, d.err)
+			}
+			if _, err := w.Write(encoder(e)); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("record %d has an unknown type %d", recordNo, record[0])
+		}
+	}
+}
+
+// errBinaryTruncated is returned when a record ends before all of its values have been read.
+var errBinaryTruncated = errors.New("record is truncated")
+
+// binaryDecoder reads values from a single record, recording the first error encountered.
+type binaryDecoder struct {
+	data []byte
+	err  error
+	// byteLevels reads each Level as a single signed byte, as written to files of the first version.
+	byteLevels bool
+}
+
+// entry decodes an entry record, resolving its Category ID using categories.
+func (d *binaryDecoder) entry(categories map[uint64]string) Entry {
+	var e Entry
+	if id := d.uvarint(); id != 0 {
+		name, ok := categories[id]
+		if !ok && d.err == nil {
+			d.err = fmt.Errorf("undefined category %d", id)
+		}
+		e.Category = name
+	}
+	e.Sequence = d.uvarint()
+	e.Time = time.Unix(0, d.varint())
+	if d.byteLevels {
+		e.Level = Level(int8(d.byte()))
+	} else {
+		e.Level = Level(d.varint())
+	}
+	e.Message = d.string()
+	count := d.uvarint()
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		key := d.string()
+		switch fieldKind(d.byte()) {
+		case fieldInt:
+			e.Fields = append(e.Fields, Int64(key, d.varint()))
+		case fieldDuration:
+			e.Fields = append(e.Fields, Duration(key, time.Duration(d.varint())))
+		case fieldUint:
+			e.Fields = append(e.Fields, Uint64(key, d.uvarint()))
+		case fieldBool:
+			e.Fields = append(e.Fields, Bool(key, d.uvarint() == 1))
+		case fieldFloat:
+			e.Fields = append(e.Fields, Float64(key, math.Float64frombits(d.uint64())))
+		default:
+			e.Fields = append(e.Fields, String(key, d.string()))
+		}
+	}
+	return e
+}
+
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errBinaryTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *binaryDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errBinaryTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *binaryDecoder) uint64() uint64 {
+	if d.err != nil || len(d.data) < 8 {
+		d.err = errBinaryTruncated
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(d.data)
+	d.data = d.data[8:]
+	return v
+}
+
+func (d *binaryDecoder) byte() byte {
+	if d.err != nil || len(d.data) < 1 {
+		d.err = errBinaryTruncated
+		return 0
+	}
+	v := d.data[0]
+	d.data = d.data[1:]
+	return v
+}
+
+func (d *binaryDecoder) string() string {
+	n := d.uvarint()
+	if d.err != nil || uint64(len(d.data)) < n {
+		d.err = errBinaryTruncated
+		return ""
+	}
+	v := string(d.data[:n])
+	d.data = d.data[n:]
+	return v
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// decodeBinaryEntries decodes the binary log at path into its entries.
+func decodeBinaryEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	capture := func(e Entry) []byte {
+		entries = append(entries, e)
+		return nil
+	}
+	if err := DecodeBinaryLog(ioutil.Discard, f, capture); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestBinaryFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.bin")
+	f, err := OpenBinaryFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	levels := []Level{LevelDebug, LevelInfo, LevelWarning, LevelError, LevelFatal, Level(-300), Level(1000)}
+	at := time.Unix(0, 1600000000123456789)
+	for i, lvl := range levels {
+		e := Entry{
+			Sequence: uint64(i + 1),
+			Time:     at,
+			Level:    lvl,
+			Category: lvl.String(),
+			Message:  "message " + lvl.String(),
+			Fields: []Field{
+				Int("int", -42),
+				Uint64("uint", 42),
+				Bool("bool", true),
+				Float64("float", 1.5),
+				Duration("duration", time.Second),
+				String("string", "value"),
+			},
+		}
+		if err := f.WriteEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := decodeBinaryEntries(t, path)
+	if len(entries) != len(levels) {
+		t.Fatalf("expected %d entries, got %d", len(levels), len(entries))
+	}
+	for i, e := range entries {
+		if e.Level != levels[i] {
+			t.Errorf("entry %d has Level %d, expected %d", i, e.Level, levels[i])
+		}
+		if e.Sequence != uint64(i+1) || !e.Time.Equal(at) || e.Category != levels[i].String() {
+			t.Errorf("entry %d was not decoded correctly: %+v", i, e)
+		}
+		if len(e.Fields) != 6 {
+			t.Fatalf("entry %d has %d fields, expected 6", i, len(e.Fields))
+		}
+		var text bytes.Buffer
+		for _, field := range e.Fields {
+			text.WriteString(field.Key + "=" + field.String() + " ")
+		}
+		if expected := "int=-42 uint=42 bool=true float=1.5 duration=1s string=value "; text.String() != expected {
+			t.Errorf("entry %d has fields %q, expected %q", i, text.String(), expected)
+		}
+	}
+}
+
+func TestDecodeBinaryLogVersion1(t *testing.T) {
+	// version 1 files store the Level as a single byte, so LevelDebug was written as 255
+	record := []byte{binaryEntryRecord}
+	record = binary.AppendUvarint(record, 0)
+	record = binary.AppendUvarint(record, 1)
+	record = binary.AppendVarint(record, 0)
+	record = append(record, byte(0xff))
+	record = appendBinaryString(record, "debug")
+	record = binary.AppendUvarint(record, 0)
+
+	path := filepath.Join(t.TempDir(), "v1.bin")
+	data := appendBinaryRecord([]byte(binaryFileMagicV1), record)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := decodeBinaryEntries(t, path)
+	if len(entries) != 1 || entries[0].Level != LevelDebug || entries[0].Message != "debug" {
+		t.Errorf("expected a single debug entry, got %+v", entries)
+	}
+
+	if _, err := OpenBinaryFile(path); err == nil {
+		t.Error("expected appending to a version 1 file to fail")
+	}
+}
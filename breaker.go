@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a BreakerSink while its circuit is open. The entry is then handled by the Logger's
+// ErrorPolicy, i.e. written to the fallback writer. Writes failing with ErrCircuitOpen are never retried.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// BreakerSink wraps a Sink in a circuit breaker. After Threshold consecutive failures the circuit opens and entries are
+// rejected immediately with ErrCircuitOpen, rather than waiting on a dead endpoint and stalling the log poller. Once
+// Cooldown has elapsed a single entry is let through to probe the Sink: if it succeeds the circuit closes, otherwise it
+// stays open for another Cooldown.
+type BreakerSink struct {
+	Sink Sink
+	// Threshold is the number of consecutive failures which open the circuit (default of 5).
+	Threshold int
+	// Cooldown is how long the circuit stays open before the Sink is probed (default of 30s).
+	Cooldown time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+}
+
+// NewBreakerSink creates a BreakerSink wrapping sink with the default threshold and cooldown.
+func NewBreakerSink(sink Sink) *BreakerSink {
+	return &BreakerSink{
+		Sink:      sink,
+		Threshold: 5,
+		Cooldown:  30 * time.Second,
+	}
+}
+
+// WriteEntry writes the entry to the wrapped Sink, unless the circuit is open.
+func (s *BreakerSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	if s.open && now().Sub(s.openedAt) < s.Cooldown {
+		s.mu.Unlock()
+		return ErrCircuitOpen
+	}
+	s.mu.Unlock()
+
+	err := s.Sink.WriteEntry(e)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.failures = 0
+		s.open = false
+		return nil
+	}
+
+	s.failures++
+	threshold := s.Threshold
+	if threshold <= 0 {
+		threshold = 5
+	}
+	// a failed probe, or reaching the threshold, (re)opens the circuit for another cooldown
+	if s.open || s.failures >= threshold {
+		s.open = true
+		s.openedAt = now()
+	}
+	return err
+}
+
+// Write implements io.Writer so that a BreakerSink can be used as a Logger's Writer. Raw writes are sent as LevelInfo
+// entries without a category.
+func (s *BreakerSink) Write(p []byte) (int, error) {
+	message := bytes.TrimSpace(p)
+	if len(message) == 0 {
+		return len(p), nil
+	}
+	e := Entry{
+		Time:    now(),
+		Level:   LevelInfo,
+		Message: string(message),
+	}
+	if err := s.WriteEntry(e); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Open reports whether the circuit is currently open.
+func (s *BreakerSink) Open() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.open
+}
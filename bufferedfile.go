@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultBufferedFileFlush is how often a BufferedFile writes its buffer to the file when its SyncPolicy does not
+// determine it.
+const defaultBufferedFileFlush = time.Second
+
+// SyncMode determines when a BufferedFile commits its content to stable storage with fsync.
+type SyncMode int
+
+const (
+	// SyncModeNever leaves writing back to the operating system.
+	SyncModeNever SyncMode = iota
+	// SyncModeEveryWrite syncs after every message.
+	SyncModeEveryWrite
+	// SyncModeInterval syncs once per interval.
+	SyncModeInterval
+	// SyncModeBytes syncs once a number of bytes have been written since the previous sync.
+	SyncModeBytes
+)
+
+// SyncPolicy is the durability/performance trade-off of a BufferedFile: messages which have not been synced may be
+// lost if the machine crashes, and messages still in the buffer may be lost if the process crashes.
+type SyncPolicy struct {
+	Mode     SyncMode
+	Interval time.Duration
+	Bytes    int
+}
+
+// SyncNever creates a SyncPolicy which never syncs. The buffer is written to the file every second.
+func SyncNever() SyncPolicy {
+	return SyncPolicy{Mode: SyncModeNever}
+}
+
+// SyncEveryWrite creates a SyncPolicy which writes and syncs every message, the most durable and slowest policy.
+func SyncEveryWrite() SyncPolicy {
+	return SyncPolicy{Mode: SyncModeEveryWrite}
+}
+
+// SyncEvery creates a SyncPolicy which writes the buffer and syncs once per interval.
+func SyncEvery(interval time.Duration) SyncPolicy {
+	return SyncPolicy{Mode: SyncModeInterval, Interval: interval}
+}
+
+// SyncEveryBytes creates a SyncPolicy which writes the buffer and syncs once n bytes have been written since the
+// previous sync. The buffer is also written to the file every second.
+func SyncEveryBytes(n int) SyncPolicy {
+	return SyncPolicy{Mode: SyncModeBytes, Bytes: n}
+}
+
+// BufferedFile is a Writer which appends to a file through an in-memory buffer, syncing it to stable storage according
+// to its SyncPolicy, so that the durability/performance trade-off of file logging can be chosen explicitly. Like a File,
+// it can be reopened for log rotation. Close must be called to write the buffer before the program terminates.
+type BufferedFile struct {
+	mu       sync.Mutex
+	path     string
+	perm     os.FileMode
+	file     *os.File
+	buf      *bufio.Writer
+	policy   SyncPolicy
+	unsynced int
+
+	exitCh chan struct{}
+	doneCh chan struct{}
+}
+
+// OpenBufferedFile opens the file at path for appending through a buffer of bufferSize bytes (default of 4096),
+// creating it with the provided permissions if necessary.
+func OpenBufferedFile(path string, perm os.FileMode, bufferSize int, policy SyncPolicy) (*BufferedFile, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &BufferedFile{
+		path:   path,
+		perm:   perm,
+		file:   file,
+		buf:    bufio.NewWriterSize(file, bufferSize),
+		policy: policy,
+		exitCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	interval := defaultBufferedFileFlush
+	if policy.Mode == SyncModeInterval && policy.Interval > 0 {
+		interval = policy.Interval
+	}
+	go f.run(interval)
+	return f, nil
+}
+
+func (f *BufferedFile) run(interval time.Duration) {
+	defer close(f.doneCh)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			f.mu.Lock()
+			f.buf.Flush()
+			if f.policy.Mode == SyncModeInterval && f.unsynced > 0 {
+				f.syncLocked()
+			}
+			f.mu.Unlock()
+		case <-f.exitCh:
+			return
+		}
+	}
+}
+
+// Write appends p to the buffer, writing and syncing it if required by the SyncPolicy.
+func (f *BufferedFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.buf.Write(p)
+	f.unsynced += n
+	if err != nil {
+		return n, err
+	}
+
+	switch f.policy.Mode {
+	case SyncModeEveryWrite:
+		err = f.syncLocked()
+	case SyncModeBytes:
+		if f.unsynced >= f.policy.Bytes {
+			err = f.syncLocked()
+		}
+	}
+	return n, err
+}
+
+// Flush writes the buffer to the file without syncing it.
+func (f *BufferedFile) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.buf.Flush()
+}
+
+// Sync writes the buffer to the file and commits it to stable storage, regardless of the SyncPolicy.
+func (f *BufferedFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.syncLocked()
+}
+
+func (f *BufferedFile) syncLocked() error {
+	if err := f.buf.Flush(); err != nil {
+		return err
+	}
+	f.unsynced = 0
+	return f.file.Sync()
+}
+
+// Reopen writes the buffer to the file, then closes it and opens its path again, so that writing continues to a new
+// file once the old one has been moved aside by log rotation.
+func (f *BufferedFile) Reopen() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, f.perm)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.finishLocked(); err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.buf.Reset(file)
+	return nil
+}
+
+// Close stops the background flushing, writes the buffer to the file, syncing it unless the SyncPolicy is
+// SyncModeNever, and closes the file.
+func (f *BufferedFile) Close() error {
+	close(f.exitCh)
+	<-f.doneCh
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.finishLocked()
+}
+
+// finishLocked writes the buffer to the current file, syncs it according to the SyncPolicy and closes it.
+func (f *BufferedFile) finishLocked() error {
+	var err error
+	if f.policy.Mode == SyncModeNever {
+		err = f.buf.Flush()
+	} else {
+		err = f.syncLocked()
+	}
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time to the Timestamp component and to time-based features such as rate limiting and
+// circuit breaking. Replacing the Clock with SetClock allows tests to control time, i.e. with a ManualClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// TimerClock is a Clock which can also call functions once time has elapsed on it. Features which run in the
+// background, such as rate limit and duplicate summaries, the statistics and counter summaries, the heartbeat and
+// silence watchdogs, are scheduled with the Clock if it is a TimerClock, and with the system clock otherwise.
+// ManualClock is a TimerClock.
+type TimerClock interface {
+	Clock
+	// AfterFunc calls f once d has elapsed on the Clock, returning a function which stops the call if it has not been
+	// made yet and reports whether it was stopped.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// systemClock is the default Clock, which reads the system time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+var (
+	clock   Clock = systemClock{}
+	clockMu sync.RWMutex
+	// processStart is the time the package was initialised, from which heartbeat uptime is measured.
+	processStart = time.Now()
+	// clockStart is the time from which elapsed timestamps are measured: processStart, or the time of the Clock when it
+	// was last set.
+	clockStart = processStart
+)
+
+// SetClock sets the Clock used to timestamp messages and drive time-based features. Elapsed timestamps are measured
+// from the current time of the new Clock. A nil Clock restores the system clock, and elapsed timestamps are measured
+// from the start of the process again.
+func SetClock(c Clock) {
+	start := processStart
+	if c == nil {
+		c = systemClock{}
+	} else {
+		start = c.Now()
+	}
+	clockMu.Lock()
+	clock = c
+	clockStart = start
+	clockMu.Unlock()
+}
+
+// elapsedStart returns the time from which elapsed timestamps are measured.
+func elapsedStart() time.Time {
+	if deterministic {
+		return DeterministicTime
+	}
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return clockStart
+}
+
+// now returns the time to timestamp a message with, read from the Clock unless deterministic mode is enabled.
+func now() time.Time {
+	if deterministic {
+		return DeterministicTime
+	}
+	clockMu.RLock()
+	c := clock
+	clockMu.RUnlock()
+	return c.Now()
+}
+
+// afterFunc calls f once d has elapsed on the Clock, returning a function which stops it. Functions already scheduled
+// are not moved to a Clock set afterwards.
+func afterFunc(d time.Duration, f func()) func() bool {
+	clockMu.RLock()
+	c := clock
+	clockMu.RUnlock()
+	if tc, ok := c.(TimerClock); ok {
+		return tc.AfterFunc(d, f)
+	}
+	return time.AfterFunc(d, f).Stop
+}
+
+// clockTimer schedules a function with afterFunc, and can be stopped so that no further calls are scheduled.
+type clockTimer struct {
+	mu      sync.Mutex
+	stopped bool
+	cancel  func() bool
+}
+
+// reset schedules f to be called once d has elapsed on the Clock, replacing any call already scheduled, unless the
+// timer has been stopped.
+func (t *clockTimer) reset(d time.Duration, f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return
+	}
+	if t.cancel != nil {
+		t.cancel()
+	}
+	t.cancel = afterFunc(d, f)
+}
+
+// stop cancels any scheduled call and prevents further calls being scheduled.
+func (t *clockTimer) stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	if t.cancel != nil {
+		t.cancel()
+	}
+}
+
+// isStopped reports whether the timer has been stopped.
+func (t *clockTimer) isStopped() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stopped
+}
+
+// every calls f every interval on the Clock until the returned function is called. Each call is scheduled once the
+// previous one has returned, so calls never overlap.
+func every(interval time.Duration, f func()) (stop func()) {
+	t := &clockTimer{}
+	var tick func()
+	tick = func() {
+		if t.isStopped() {
+			return
+		}
+		f()
+		t.reset(interval, tick)
+	}
+	t.reset(interval, tick)
+	return t.stop
+}
+
+// ManualClock is a Clock which only advances when it is told to, for deterministic tests and simulated time. Functions
+// scheduled with AfterFunc are called by Add and Set before they return, in the order they are due, with the clock set
+// to the time each one is due.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// manualTimer is a function scheduled on a ManualClock.
+type manualTimer struct {
+	at time.Time
+	f  func()
+}
+
+// NewManualClock creates a ManualClock set to the provided time.
+func NewManualClock(t time.Time) *ManualClock {
+	return &ManualClock{now: t}
+}
+
+// Now returns the ManualClock's current time.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set sets the ManualClock's current time, calling any scheduled functions which are then due.
+func (c *ManualClock) Set(t time.Time) {
+	c.advance(func(time.Time) time.Time {
+		return t
+	})
+}
+
+// Add advances the ManualClock by d, calling any scheduled functions which are then due.
+func (c *ManualClock) Add(d time.Duration) {
+	c.advance(func(now time.Time) time.Time {
+		return now.Add(d)
+	})
+}
+
+// AfterFunc calls f once the ManualClock has been advanced by at least d.
+func (c *ManualClock) AfterFunc(d time.Duration, f func()) func() bool {
+	t := &manualTimer{at: c.Now().Add(d), f: f}
+	c.mu.Lock()
+	c.timers = append(c.timers, t)
+	c.mu.Unlock()
+
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for i, timer := range c.timers {
+			if timer == t {
+				c.timers = append(c.timers[:i], c.timers[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// advance moves the ManualClock to the time returned by target, calling each scheduled function which is due in order,
+// with the clock set to the time it was due. Functions scheduled by those calls are also called if they are due.
+func (c *ManualClock) advance(target func(now time.Time) time.Time) {
+	c.mu.Lock()
+	end := target(c.now)
+	for {
+		next := -1
+		for i, t := range c.timers {
+			if !t.at.After(end) && (next < 0 || t.at.Before(c.timers[next].at)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+
+		t := c.timers[next]
+		c.timers = append(c.timers[:next], c.timers[next+1:]...)
+		if t.at.After(c.now) {
+			c.now = t.at
+		}
+		c.mu.Unlock()
+		t.f()
+		c.mu.Lock()
+	}
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitFollowsManualClock(t *testing.T) {
+	c := NewManualClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	SetClock(c)
+	defer SetClock(nil)
+
+	var buf bytes.Buffer
+	l := NewLogger(&buf, "RATE", true)
+	l.SetRateLimit(1, time.Minute, 1)
+	l.Log("first")
+	l.Log("suppressed")
+	l.Log("suppressed")
+
+	// the window closes on the Clock, without waiting in real time or for another message
+	c.Add(time.Minute)
+	Flush(time.Second)
+	if !strings.Contains(buf.String(), "2 messages suppressed") {
+		t.Fatalf("expected a summary once the window closed, got %q", buf.String())
+	}
+}
+
+func TestSilenceFollowsManualClock(t *testing.T) {
+	c := NewManualClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	SetClock(c)
+	defer SetClock(nil)
+
+	var alerts []time.Duration
+	l := NewLogger(&bytes.Buffer{}, "QUIET", true)
+	stop := l.WatchSilence(time.Minute, func(category string, silence time.Duration) {
+		alerts = append(alerts, silence)
+	})
+	defer stop()
+
+	c.Add(30 * time.Second)
+	if len(alerts) != 0 {
+		t.Fatalf("alerted before the timeout: %v", alerts)
+	}
+	c.Add(30 * time.Second)
+	if len(alerts) != 1 || alerts[0] != time.Minute {
+		t.Fatalf("expected one alert after a minute of silence, got %v", alerts)
+	}
+	// only one alert per silence
+	c.Add(5 * time.Minute)
+	if len(alerts) != 1 {
+		t.Fatalf("expected one alert per silence, got %v", alerts)
+	}
+}
+
+func TestManualClockAfterFunc(t *testing.T) {
+	c := NewManualClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	var fired []time.Time
+	c.AfterFunc(2*time.Second, func() { fired = append(fired, c.Now()) })
+	stop := c.AfterFunc(time.Second, func() { t.Fatal("stopped function was called") })
+	if !stop() {
+		t.Fatal("expected the function to be stopped")
+	}
+	c.Add(3 * time.Second)
+	if len(fired) != 1 || !fired[0].Equal(time.Date(2020, 1, 1, 0, 0, 2, 0, time.UTC)) {
+		t.Fatalf("expected one call at the time it was due, got %v", fired)
+	}
+}
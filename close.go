@@ -0,0 +1,44 @@
+package logger
+
+import "io"
+
+// NewFileLogger creates a new Logger which appends to the file at path, creating it if necessary. The Logger owns the
+// file, so it is closed by Close. The file is a File, so can be reopened for log rotation with ReopenFiles.
+func NewFileLogger(path string, category string, enabled bool) (*Logger, error) {
+	file, err := OpenFile(path, 0600)
+	if err != nil {
+		return nil, err
+	}
+	l := NewLogger(file, category, enabled)
+	l.owned = file
+	return l, nil
+}
+
+// SetOwnedWriter sets the Logger's Writer and makes the Logger responsible for closing it, i.e. for a file which only
+// this Logger writes to. The Writer is closed by Close if it implements io.Closer.
+func (l *Logger) SetOwnedWriter(w io.Writer) {
+	l.stateMu.Lock()
+	l.Writer = w
+	l.stateMu.Unlock()
+	l.owned = w
+}
+
+// Close disables the Logger, waits for the messages it has already queued to be written and then closes the Writer it
+// owns, if any (see NewFileLogger and SetOwnedWriter). Writers which are not owned by the Logger are left open, as they
+// may be shared with other loggers. The poller must be running for queued messages to be written.
+func (l *Logger) Close() error {
+	l.SetEnabled(false)
+
+	done := make(chan struct{})
+	afterQueued(func() {
+		close(done)
+	})
+	<-done
+
+	owned := l.owned
+	l.owned = nil
+	if closer, ok := owned.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jemgunay/logger"
+)
+
+// benchSinks create the Writers which bench compares, in dir. close is called once each run has been flushed.
+var benchSinks = map[string]func(dir string) (w io.Writer, close func() error, err error){
+	"discard": func(string) (io.Writer, func() error, error) {
+		return io.Discard, func() error { return nil }, nil
+	},
+	"file": func(dir string) (io.Writer, func() error, error) {
+		f, err := logger.OpenFile(filepath.Join(dir, "file.log"), 0600)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f.Close, nil
+	},
+	"buffered": func(dir string) (io.Writer, func() error, error) {
+		f, err := logger.OpenBufferedFile(filepath.Join(dir, "buffered.log"), 0600, 64*1024, logger.SyncNever())
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f.Close, nil
+	},
+	"mapped": func(dir string) (io.Writer, func() error, error) {
+		f, err := logger.OpenMappedFile(filepath.Join(dir, "mapped.log"), 0, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f.Close, nil
+	},
+	"gzip": func(dir string) (io.Writer, func() error, error) {
+		f, err := logger.OpenFile(filepath.Join(dir, "gzip.log.gz"), 0600)
+		if err != nil {
+			return nil, nil, err
+		}
+		gz := logger.NewGzipWriter(f, time.Second)
+		return gz, func() error {
+			if err := gz.Close(); err != nil {
+				f.Close()
+				return err
+			}
+			return f.Close()
+		}, nil
+	},
+}
+
+// benchResult is the measurement of a single bench run.
+type benchResult struct {
+	mode, encoder, sink string
+	elapsed             time.Duration
+	allocs, bytes       uint64
+}
+
+// bench measures the throughput and allocations of logging on this machine in buffered and unbuffered mode, with each
+// encoder and Writer, printing a comparison table to guide configuration, i.e.
+// logger bench -n 200000 -goroutines 8 -sinks discard,buffered
+func bench(args []string) {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	n := flags.Int("n", 100000, "number of messages logged by each run")
+	goroutines := flags.Int("goroutines", 1, "number of goroutines logging concurrently")
+	modes := flags.String("modes", "buffered,unbuffered", "comma separated modes to compare")
+	encoders := flags.String("encoders", "text,color,json", "comma separated encoders to compare")
+	sinks := flags.String("sinks", "discard,file,buffered,mapped,gzip", "comma separated writers to compare")
+	flags.Parse(args)
+
+	if *n <= 0 || *goroutines <= 0 {
+		fmt.Fprintln(os.Stderr, "bench: -n and -goroutines must be positive")
+		os.Exit(2)
+	}
+	for _, name := range splitList(*modes) {
+		if name != "buffered" && name != "unbuffered" {
+			fmt.Fprintf(os.Stderr, "bench: invalid mode %q: must be buffered or unbuffered\n", name)
+			os.Exit(2)
+		}
+	}
+	for _, name := range splitList(*encoders) {
+		if _, ok := logger.ParseEncoder(name); !ok {
+			fmt.Fprintf(os.Stderr, "bench: invalid encoder %q: must be text, color or json\n", name)
+			os.Exit(2)
+		}
+	}
+	for _, name := range splitList(*sinks) {
+		if benchSinks[name] == nil {
+			fmt.Fprintf(os.Stderr, "bench: invalid sink %q: must be discard, file, buffered, mapped or gzip\n", name)
+			os.Exit(2)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "logger-bench")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bench: %s\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(dir)
+
+	logger.StartPoller()
+	var results []benchResult
+	for _, mode := range splitList(*modes) {
+		for _, encoder := range splitList(*encoders) {
+			for _, sink := range splitList(*sinks) {
+				fmt.Fprintf(os.Stderr, "running %s %s %s...\n", mode, encoder, sink)
+				r, err := benchRun(dir, mode, encoder, sink, *n, *goroutines)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "bench: %s %s %s: %s\n", mode, encoder, sink, err)
+					os.Exit(1)
+				}
+				results = append(results, r)
+			}
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "mode\tencoder\tsink\tmsgs/s\tns/msg\tallocs/msg\tB/msg\t")
+	for _, r := range results {
+		perMsg := float64(*n)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.0f\t%.0f\t%.1f\t%.0f\t\n", r.mode, r.encoder, r.sink,
+			perMsg/r.elapsed.Seconds(), float64(r.elapsed.Nanoseconds())/perMsg,
+			float64(r.allocs)/perMsg, float64(r.bytes)/perMsg)
+	}
+	w.Flush()
+}
+
+// benchRun logs n messages split across goroutines, timing them until they have all been written.
+func benchRun(dir, mode, encoderName, sinkName string, n, goroutines int) (benchResult, error) {
+	w, closeSink, err := benchSinks[sinkName](dir)
+	if err != nil {
+		return benchResult{}, err
+	}
+	encoder, _ := logger.ParseEncoder(encoderName)
+	// every encoder is written through a MultiSink, so that they are compared like for like
+	multi := logger.MultiWriter()
+	multi.AddEncoded(w, encoder, logger.LevelDebug)
+
+	l := logger.NewLogger(multi, "BENCH", true)
+	defer l.Disable()
+	logger.SetBuffered(mode == "buffered")
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		count := n / goroutines
+		if g < n%goroutines {
+			count++
+		}
+		wg.Add(1)
+		go func(count int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				l.LogFields("request handled", logger.Str("path", "/api/users"), logger.Int("status", 200),
+					logger.Duration("latency", 1250*time.Microsecond))
+			}
+		}(count)
+	}
+	wg.Wait()
+	if !logger.Flush(time.Minute) {
+		return benchResult{}, fmt.Errorf("timed out waiting for messages to be written")
+	}
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	if err := closeSink(); err != nil {
+		return benchResult{}, err
+	}
+	return benchResult{
+		mode:    mode,
+		encoder: encoderName,
+		sink:    sinkName,
+		elapsed: elapsed,
+		allocs:  after.Mallocs - before.Mallocs,
+		bytes:   after.TotalAlloc - before.TotalAlloc,
+	}, nil
+}
+
+// splitList splits a comma separated list, ignoring empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jemgunay/logger"
+)
+
+// decode writes the contents of binary log files to Stdout as text or JSON, i.e.
+// logger decode -format json app.log.bin
+// If no files are provided, the binary log is read from Stdin.
+func decode(args []string) {
+	flags := flag.NewFlagSet("decode", flag.ExitOnError)
+	format := flags.String("format", "text", "output format: text, color or json")
+	flags.Parse(args)
+
+	encoder, ok := logger.ParseEncoder(*format)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "decode: invalid format %q\n", *format)
+		os.Exit(2)
+	}
+	// the text format of ParseEncoder is the Logger's own format, which binary logs do not record
+	if encoder == nil {
+		encoder = logger.TextEncoder
+	}
+
+	if flags.NArg() == 0 {
+		decodeFrom(os.Stdin, "stdin", encoder)
+		return
+	}
+	for _, path := range flags.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "decode: %s\n", err)
+			os.Exit(1)
+		}
+		decodeFrom(f, path, encoder)
+		f.Close()
+	}
+}
+
+// decodeFrom decodes a single binary log, exiting on failure.
+func decodeFrom(r io.Reader, name string, encoder logger.EncoderFunc) {
+	if err := logger.DecodeBinaryLog(os.Stdout, r, encoder); err != nil {
+		fmt.Fprintf(os.Stderr, "decode: %s: %s\n", name, err)
+		os.Exit(1)
+	}
+}
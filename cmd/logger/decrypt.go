@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jemgunay/logger"
+)
+
+// decrypt writes the decrypted contents of encrypted log files to Stdout, i.e.
+// logger decrypt -key 6368616e676520746869732070617373 app.log.enc
+// If no files are provided, the encrypted log is read from Stdin.
+func decrypt(args []string) {
+	flags := flag.NewFlagSet("decrypt", flag.ExitOnError)
+	hexKey := flags.String("key", os.Getenv("LOGGER_KEY"), "hex encoded AES key (default of $LOGGER_KEY)")
+	flags.Parse(args)
+
+	key, err := hex.DecodeString(*hexKey)
+	if err != nil || len(key) == 0 {
+		fmt.Fprintln(os.Stderr, "decrypt: a hex encoded key must be provided with -key or $LOGGER_KEY")
+		os.Exit(2)
+	}
+
+	if flags.NArg() == 0 {
+		decryptFrom(os.Stdin, "stdin", key)
+		return
+	}
+	for _, path := range flags.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "decrypt: %s\n", err)
+			os.Exit(1)
+		}
+		decryptFrom(f, path, key)
+		f.Close()
+	}
+}
+
+// decryptFrom decrypts a single encrypted log, exiting on failure.
+func decryptFrom(r io.Reader, name string, key []byte) {
+	if err := logger.DecryptLog(os.Stdout, r, key); err != nil {
+		fmt.Fprintf(os.Stderr, "decrypt: %s: %s\n", name, err)
+		os.Exit(1)
+	}
+}
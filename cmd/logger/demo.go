@@ -0,0 +1,272 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jemgunay/logger"
+)
+
+// demoSection is a part of the demo, showcasing one aspect of a Logger's configuration.
+type demoSection struct {
+	name    string
+	summary string
+	run     func()
+}
+
+// demoSections are the sections of the demo in the order they are shown.
+var demoSections = []demoSection{
+	{"categories", "Category formatters", demoCategories},
+	{"timestamps", "Timestamp formats and formatters", demoTimestamps},
+	{"messages", "Message formatters", demoMessages},
+	{"layout", "category padding, grouping and multi-line indentation", demoLayout},
+	{"colors", "the colour of each Level used by ColorEncoder", demoColors},
+	{"encoders", "the same entries written by each encoder", demoEncoders},
+	{"fields", "structured fields in text and JSON", demoFields},
+}
+
+// demoLoggers are the loggers created by the current section, which are removed once it has finished.
+var demoLoggers []*logger.Logger
+
+// demo showcases every formatter, encoder, Level colour and component combination, so that configurations can be
+// previewed before they are adopted, i.e.
+// logger demo -section timestamps,encoders
+func demo(args []string) {
+	flags := flag.NewFlagSet("demo", flag.ExitOnError)
+	list := flags.Bool("list", false, "list the sections of the demo")
+	var sections patternList
+	flags.Var(&sections, "section", "only show these comma separated sections")
+	flags.Parse(args)
+
+	if *list {
+		for _, s := range demoSections {
+			fmt.Printf("%-12s %s\n", s.name, s.summary)
+		}
+		return
+	}
+	for _, name := range sections {
+		if !hasDemoSection(name) {
+			fmt.Fprintf(os.Stderr, "demo: unknown section %q, see demo -list\n", name)
+			os.Exit(2)
+		}
+	}
+
+	logger.StartPoller()
+	first := true
+	for _, s := range demoSections {
+		if len(sections) > 0 && !matchAny(sections, s.name) {
+			continue
+		}
+		if !first {
+			captionf("")
+		}
+		first = false
+		captionf("=== %s: %s ===", s.name, s.summary)
+		s.run()
+		endDemoSection()
+	}
+}
+
+// hasDemoSection reports whether name matches the name of a section.
+func hasDemoSection(name string) bool {
+	for _, s := range demoSections {
+		if matchAny([]string{name}, s.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// captionf writes a description to Stdout once the messages logged before it have been written.
+func captionf(format string, args ...interface{}) {
+	logger.Flush(5 * time.Second)
+	fmt.Printf(format+"\n", args...)
+}
+
+// demoLogger creates a Logger which writes to Stdout, and is removed once the current section has finished.
+func demoLogger(category string) *logger.Logger {
+	l := logger.NewLogger(os.Stdout, category, true)
+	demoLoggers = append(demoLoggers, l)
+	return l
+}
+
+// endDemoSection waits for the section's messages to be written, then removes its loggers and restores the package
+// settings it may have changed.
+func endDemoSection() {
+	logger.Flush(5 * time.Second)
+	logger.RemoveLogger(demoLoggers...)
+	demoLoggers = nil
+	logger.SetCategoryGrouping(true)
+	logger.SetMultilineIndent(true)
+	logger.SetCategoryPadding(true)
+}
+
+func demoCategories() {
+	formatters := []struct {
+		description string
+		formatter   logger.FormatterFunc
+	}{
+		{"SquareBracketWrapper (the default)", logger.SquareBracketWrapper},
+		{"BracketWrapper", logger.BracketWrapper},
+		{"nil", nil},
+		{"a custom FormatterFunc", func(s string) string { return strings.ToLower(s) + ":" }},
+	}
+	// every entry is shown with its Category
+	logger.SetCategoryGrouping(false)
+	l := demoLogger("HTTP")
+	for _, f := range formatters {
+		captionf("Category.Formatter = %s:", f.description)
+		l.Category.Formatter = f.formatter
+		// recompute the padding for the formatted Category
+		logger.SetCategoryPadding(true)
+		l.Log("GET /api/users 200")
+	}
+}
+
+func demoTimestamps() {
+	formats := []struct {
+		description string
+		timestamp   logger.Timestamp
+	}{
+		{`"01/02 15:04:05" (the default)`, logger.Timestamp{Format: "01/02 15:04:05"}},
+		{`"06/01/02 15:04:05.00000"`, logger.Timestamp{Format: "06/01/02 15:04:05.00000"}},
+		{"time.RFC3339 in UTC", logger.Timestamp{Format: time.RFC3339, UseUTC: true}},
+		{"time.Kitchen", logger.Timestamp{Format: time.Kitchen}},
+		{"logger.TimestampUnixMilli", logger.Timestamp{Format: logger.TimestampUnixMilli}},
+		{"logger.TimestampElapsed", logger.Timestamp{Format: logger.TimestampElapsed}},
+		{"time.StampMicro with a Formatter", logger.Timestamp{
+			Format:    time.StampMicro,
+			Formatter: func(s string) string { return "<" + s + ">" },
+		}},
+		{`"" (no timestamp)`, logger.Timestamp{}},
+	}
+	// every entry is shown with its Category
+	logger.SetCategoryGrouping(false)
+	l := demoLogger("INFO")
+	for _, f := range formats {
+		captionf("Timestamp format %s:", f.description)
+		l.Timestamp = f.timestamp
+		l.Log("service started")
+	}
+}
+
+func demoMessages() {
+	alternateCase := func(s string) string {
+		var b strings.Builder
+		i := 0
+		for _, char := range s {
+			if i++; i%2 == 0 {
+				b.WriteString(strings.ToUpper(string(char)))
+				continue
+			}
+			b.WriteRune(char)
+		}
+		return b.String()
+	}
+	formatters := []struct {
+		description string
+		formatter   logger.FormatterFunc
+	}{
+		{"nil (the default)", nil},
+		{"strings.ToUpper", strings.ToUpper},
+		{"a request direction prefix", func(s string) string { return "> " + s }},
+		{"alternating case", alternateCase},
+	}
+	// every entry is shown with its Category
+	logger.SetCategoryGrouping(false)
+	l := demoLogger("OUTGOING")
+	for _, f := range formatters {
+		captionf("Message.Formatter = %s:", f.description)
+		l.Message.Formatter = f.formatter
+		l.Log("request sent to http://example.com")
+	}
+}
+
+func demoLayout() {
+	info := demoLogger("INFO")
+	errorLogger := demoLogger("ERROR")
+	incoming := demoLogger("INCOMING")
+	write := func() {
+		info.Log("listening on :8080")
+		for i := 1; i <= 2; i++ {
+			errorLogger.Logf("request %d failed, err:[%v]", i, errors.New("connection reset"))
+		}
+		incoming.Log("POST /upload\ncontent-type: image/png\ncontent-length: 5120")
+	}
+
+	options := []struct {
+		description               string
+		padding, grouping, indent bool
+	}{
+		{"With padding, grouping and multi-line indentation (the defaults)", true, true, true},
+		{"Without category grouping", true, false, true},
+		{"Without category padding", false, true, true},
+		{"Without multi-line indentation", true, true, false},
+	}
+	for _, o := range options {
+		captionf("%s:", o.description)
+		logger.SetCategoryPadding(o.padding)
+		logger.SetCategoryGrouping(o.grouping)
+		logger.SetMultilineIndent(o.indent)
+		write()
+		// wait for the messages to be written before the settings change
+		logger.Flush(5 * time.Second)
+	}
+}
+
+func demoColors() {
+	multi := logger.MultiWriter()
+	multi.AddEncoded(os.Stdout, logger.ColorEncoder, logger.LevelDebug)
+	for _, lvl := range []logger.Level{logger.LevelDebug, logger.LevelInfo, logger.LevelWarning, logger.LevelError,
+		logger.LevelFatal} {
+		l := demoLogger(lvl.String())
+		l.Writer = multi
+		l.Logf("a message logged at Level %s", lvl)
+	}
+}
+
+func demoEncoders() {
+	for _, name := range []string{"text", "color", "json"} {
+		encoder, _ := logger.ParseEncoder(name)
+		description := fmt.Sprintf("the %s encoder", name)
+		if encoder == nil {
+			description = "the Logger's own format (text)"
+		}
+		captionf("Written by %s:", description)
+
+		multi := logger.MultiWriter()
+		multi.AddEncoded(os.Stdout, encoder, logger.LevelDebug)
+		info := demoLogger("INFO")
+		warning := demoLogger("WARNING")
+		info.Writer, warning.Writer = multi, multi
+		info.Log("cache warmed")
+		warning.LogFields("cache nearly full", logger.Int("entries", 9500), logger.Int("capacity", 10000))
+		logger.Flush(5 * time.Second)
+		logger.RemoveLogger(info, warning)
+	}
+}
+
+func demoFields() {
+	fields := []logger.Field{
+		logger.Str("method", "GET"),
+		logger.Str("path", "/api/users"),
+		logger.Int("status", 200),
+		logger.Duration("latency", 1250*time.Microsecond),
+		logger.Bool("cached", true),
+		logger.Str("agent", "curl/8.5.0 (x86_64)"),
+	}
+	l := demoLogger("HTTP")
+	captionf("Text, quoting values which contain spaces:")
+	l.LogFields("request handled", fields...)
+	logger.Flush(5 * time.Second)
+
+	captionf("JSON, with typed values:")
+	multi := logger.MultiWriter()
+	multi.AddEncoded(os.Stdout, logger.JSONEncoder, logger.LevelDebug)
+	l.Writer = multi
+	l.LogFields("request handled", fields...)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jemgunay/logger"
+)
+
+// timestampLayouts are the timestamp layouts recognised in text logs: the default Timestamp Format of NewLogger, the
+// layout documented as the Timestamp default, the RFC3339 timestamps of audit logs and the Timestamp Format presets
+// which are not layouts.
+var timestampLayouts = []string{
+	"01/02 15:04:05",
+	"06/01/02 15:04:05.00000",
+	time.RFC3339Nano,
+	logger.TimestampUnix,
+	logger.TimestampUnixMilli,
+	logger.TimestampUnixNano,
+	logger.TimestampElapsed,
+}
+
+// presetParsers parse the timestamps of the Timestamp Format presets which cannot be parsed as layouts. Unix timestamps
+// are only recognised with the number of digits they have between 2001 and 2286, so that a message which starts with a
+// number is not mistaken for one.
+var presetParsers = map[string]func(string) (time.Time, bool){
+	logger.TimestampUnix: func(s string) (time.Time, bool) {
+		n, ok := parseDigits(s, 10)
+		return time.Unix(n, 0), ok
+	},
+	logger.TimestampUnixMilli: func(s string) (time.Time, bool) {
+		n, ok := parseDigits(s, 13)
+		return time.Unix(0, n*int64(time.Millisecond)), ok
+	},
+	logger.TimestampUnixNano: func(s string) (time.Time, bool) {
+		n, ok := parseDigits(s, 19)
+		return time.Unix(0, n), ok
+	},
+	logger.TimestampElapsed: parseElapsed,
+}
+
+// parseDigits parses s as a decimal number of exactly the provided number of digits.
+func parseDigits(s string, digits int) (int64, bool) {
+	if len(s) != digits || digits == 0 || s[0] == '+' || s[0] == '-' {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+// parseElapsed parses a TimestampElapsed timestamp, i.e. "+00:03.412" or "+1:02:03.412". Elapsed timestamps are
+// relative to the start of the process which wrote them, so they are returned as the time elapsed since the zero time,
+// which orders the entries of a single log but not those of different logs.
+func parseElapsed(s string) (time.Time, bool) {
+	if len(s) < len("+00:00.000") || (s[0] != '+' && s[0] != '-') || s[len(s)-4] != '.' {
+		return time.Time{}, false
+	}
+	ms, ok := parseDigits(s[len(s)-3:], 3)
+	if !ok {
+		return time.Time{}, false
+	}
+	d := time.Duration(ms) * time.Millisecond
+
+	// hours are only written once an hour has elapsed
+	parts := strings.Split(s[1:len(s)-4], ":")
+	if len(parts) == 3 {
+		hours, ok := parseDigits(parts[0], len(parts[0]))
+		if !ok {
+			return time.Time{}, false
+		}
+		d += time.Duration(hours) * time.Hour
+		parts = parts[1:]
+	}
+	if len(parts) != 2 {
+		return time.Time{}, false
+	}
+	minutes, ok := parseDigits(parts[0], 2)
+	seconds, ok2 := parseDigits(parts[1], 2)
+	if !ok || !ok2 || minutes >= 60 || seconds >= 60 {
+		return time.Time{}, false
+	}
+	d += time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	if s[0] == '-' {
+		d = -d
+	}
+	return time.Time{}.Add(d), true
+}
+
+// logEntry is an entry read from a log file: a line starting with a timestamp and any continuation lines after it.
+type logEntry struct {
+	time time.Time
+	// lines are the parsed lines of the entry, and raw are the lines as written.
+	lines []textLine
+	raw   []string
+	// entry is the entry as it was logged, as far as it can be recovered from text.
+	entry logger.Entry
+}
+
+// logReader reads the entries of a text or JSON log one at a time, holding the next one back until it is complete.
+type logReader struct {
+	name    string
+	reader  *bufio.Reader
+	layouts []string
+
+	// entry is the entry at the head of the log, or nil once the log has been read entirely.
+	entry   *logEntry
+	pending *logEntry
+	prev    textLine
+	last    time.Time
+}
+
+// newLogReader creates a logReader which recognises timestamps in the provided layouts, and reads its first entry.
+func newLogReader(name string, r io.Reader, layouts []string) (*logReader, error) {
+	lr := &logReader{
+		name:    name,
+		reader:  bufio.NewReader(r),
+		layouts: layouts,
+	}
+	return lr, lr.next()
+}
+
+// next reads the following entry of the log into entry.
+func (r *logReader) next() error {
+	r.entry = nil
+	for r.entry == nil {
+		raw, err := r.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(raw) > 0 {
+			r.add(string(bytes.TrimRight(raw, "\r\n")))
+		}
+		if err == io.EOF {
+			if r.entry == nil {
+				r.entry, r.pending = r.pending, nil
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// add appends a line to the pending entry, or starts a new one if the line has a timestamp, at which point the pending
+// entry is complete.
+func (r *logReader) add(raw string) {
+	var lines []textLine
+	var e logger.Entry
+	ok := false
+	if parsed, err := parseJSONEntry([]byte(raw)); err == nil {
+		e, ok = parsed, true
+		lines = entryTextLines(e, e.Time.Local().Format(timestampLayouts[0]))
+	} else {
+		l := parseTextLine(raw, r.prev)
+		r.prev = l
+		lines = []textLine{l}
+		e = logger.Entry{Level: l.level, Category: l.category, Message: l.text}
+		if !l.continuation {
+			e.Time, e.Message, ok = parseLineTime(l.text, r.layouts)
+		}
+	}
+
+	if !ok || r.pending == nil {
+		if r.pending == nil {
+			// lines before the first timestamp of the log are ordered with the previous entry
+			r.pending = &logEntry{time: r.last, entry: e}
+		} else {
+			r.pending.entry.Message += "\n" + e.Message
+		}
+		r.pending.lines = append(r.pending.lines, lines...)
+		r.pending.raw = append(r.pending.raw, raw)
+		if ok {
+			r.pending.time, r.last = e.Time, e.Time
+			r.pending.entry = e
+		}
+		return
+	}
+	r.entry = r.pending
+	r.pending = &logEntry{time: e.Time, lines: lines, raw: []string{raw}, entry: e}
+	r.last = e.Time
+}
+
+// parseLineTime parses the timestamp at the start of text using the first layout which matches it, returning the text
+// which follows it. Timestamps without a year are assumed to be from the current year, so that they can be ordered
+// against those which have one.
+func parseLineTime(text string, layouts []string) (time.Time, string, bool) {
+	for _, layout := range layouts {
+		value := text
+		if parse, ok := presetParsers[layout]; ok {
+			// presets are delimited by the following space
+			if i := strings.IndexByte(text, ' '); i >= 0 {
+				value = text[:i]
+			}
+			if ts, ok := parse(value); ok {
+				return ts, strings.TrimPrefix(text[len(value):], " "), true
+			}
+			continue
+		}
+		if strings.Contains(layout, "Z07") {
+			// zoned layouts vary in length, so are delimited by the following space
+			if i := strings.IndexByte(text, ' '); i >= 0 {
+				value = text[:i]
+			}
+		} else if len(text) >= len(layout) {
+			value = text[:len(layout)]
+		}
+		ts, err := time.ParseInLocation(layout, value, time.Local)
+		if err != nil {
+			continue
+		}
+		if ts.Year() == 0 {
+			ts = ts.AddDate(time.Now().Year(), 0, 0)
+		}
+		return ts, strings.TrimPrefix(text[len(value):], " "), true
+	}
+	return time.Time{}, text, false
+}
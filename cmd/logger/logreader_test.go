@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jemgunay/logger"
+)
+
+func TestParseLineTimePresets(t *testing.T) {
+	at := time.Date(2021, 3, 4, 5, 6, 7, 891234567, time.Local)
+	elapsed := 62*time.Minute + 3*time.Second + 412*time.Millisecond
+
+	tests := []struct {
+		format   string
+		expected time.Time
+	}{
+		// timestamps without a year are assumed to be from the current year
+		{"01/02 15:04:05", at.AddDate(time.Now().Year()-at.Year(), 0, 0).Truncate(time.Second)},
+		{logger.TimestampRFC3339, at.Truncate(time.Second)},
+		{logger.TimestampRFC3339Nano, at},
+		{logger.TimestampUnix, at.Truncate(time.Second)},
+		{logger.TimestampUnixMilli, at.Truncate(time.Millisecond)},
+		{logger.TimestampUnixNano, at},
+		{logger.TimestampElapsed, time.Time{}.Add(elapsed)},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			// elapsed timestamps are measured from when the Clock was set
+			c := logger.NewManualClock(at.Add(-elapsed))
+			logger.SetClock(c)
+			defer logger.SetClock(nil)
+			c.Set(at)
+
+			ts := &logger.Timestamp{Format: test.format}
+			line := ts.Compose() + " message"
+			parsed, rest, ok := parseLineTime(line, timestampLayouts)
+			if !ok {
+				t.Fatalf("failed to parse %q", line)
+			}
+			if !parsed.Equal(test.expected) || rest != "message" {
+				t.Errorf("parsed %q as %s followed by %q, expected %s", line, parsed, rest, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseLineTimeRejectsNumbers(t *testing.T) {
+	for _, text := range []string{"42 items processed", "+1:2:3.4 retries", "1234567890123456 bytes"} {
+		if parsed, _, ok := parseLineTime(text, timestampLayouts); ok {
+			t.Errorf("expected %q not to have a timestamp, got %s", text, parsed)
+		}
+	}
+}
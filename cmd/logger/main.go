@@ -1,93 +1,60 @@
 package main
 
 import (
-	"errors"
-	"github.com/jemgunay/logger"
+	"fmt"
 	"os"
-	"strings"
-	"time"
+	"sort"
 )
 
-var (
-	Plain     = &logger.Logger{Writer: os.Stdout, Enabled: true}
-	Info      = logger.NewLogger(os.Stdout, "INFO", false)
-	Error     = logger.NewLogger(os.Stderr, "ERROR", true)
-	Incoming  = logger.NewLogger(os.Stdout, "INCOMING", true)
-	Outgoing  = logger.NewLogger(os.Stdout, "OUTGOING", true)
-	File      = logger.NewLogger(nil, "FILE", true)
-	Formatted = logger.NewLogger(os.Stdout, "FORMATTED", true)
-)
-
-func main() {
-	example()
-	time.Sleep(time.Millisecond)
+// command is a subcommand of the logger CLI.
+type command struct {
+	run     func(args []string)
+	summary string
 }
 
-func example() {
-	/*
-	 * Enable a previously disabled logger.
-	 */
-	Info.Enable()
-	Info.Log("this logger has been enabled")
+// commands are the subcommands of the logger CLI, keyed by name.
+var commands = map[string]command{
+	"bench":   {bench, "compare the throughput and allocations of modes, encoders and writers"},
+	"decode":  {decode, "convert binary log files to text or JSON"},
+	"decrypt": {decrypt, "decrypt encrypted log files"},
+	"demo":    {demo, "preview every formatter, encoder, Level colour and component combination"},
+	"merge":   {merge, "interleave log files by timestamp into one chronologically ordered stream"},
+	"pretty":  {pretty, "render JSON logs read from stdin in the aligned, coloured text format"},
+	"replay":  {replay, "re-emit a recorded log in real time to stdout or a remote sink"},
+	"tail":    {tail, "follow log files, re-applying colour, padding and category filters"},
+	"view":    {view, "browse a log interactively with follow, category and level filters and search"},
+}
 
-	/*
-	 * An error logger (outputs to Stderr).
-	 */
-	for i := 1; i <= 4; i++ {
-		err := errors.New("this is an error")
-		Error.Logf("example error message no. %v, err:[%v]", i, err.Error())
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
 	}
-
-	/*
-	 * An incoming request logger.
-	 */
-	Incoming.Message.Formatter = func(msg string) string { return "< " + msg }
-	Incoming.Logf("this is a formatted incoming request: %v", "localhost:8080/retrieve")
-
-	/*
-	 * An outgoing request logger.
-	 */
-	Outgoing.Enable()
-	Outgoing.Message.Formatter = func(msg string) string { return "> " + msg }
-	Outgoing.Logln("this is an outgoing request followed by a new line: ", "http://google.com")
-
-	/*
-	 * A disabled logger.
-	 */
-	Outgoing.Disable()
-	Outgoing.Log("this outgoing request will not be written to Stdout: ", "http://google.com")
-
-	/*
-	 * A logger which writes to a file.
-	 */
-	fileWriter, err := os.OpenFile("./test.txt", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		panic(err)
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		if os.Args[1] != "help" && os.Args[1] != "-h" && os.Args[1] != "--help" {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		}
+		usage()
+		os.Exit(2)
 	}
+	cmd.run(os.Args[2:])
+}
 
-	File.Writer = fileWriter
-	File.Log("this message has been written to a file")
-
-	/*
-	 * Provide a function to format all logged messages, e.g.
-	 */
-	// ...to capitalise whole message:
-	Formatted.Message.Formatter = strings.ToUpper
-	Formatted.Logf("this message used to be lower case")
+// usage writes the list of subcommands to Stderr.
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	// ...to alternate letter case:
-	alternateCase := func(s string) (result string) {
-		i := 0
-		for _, char := range s {
-			i++
-			if i%2 == 0 {
-				result += strings.ToUpper(string(char))
-				continue
-			}
-			result += string(char)
-		}
-		return
+	fmt.Fprintln(os.Stderr, "usage: logger <command> [flags] [files]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
 	}
-	Formatted.Message.Formatter = alternateCase
-	Formatted.Logf("this message also used to be lower case")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "run logger <command> -h for the flags of a command")
 }
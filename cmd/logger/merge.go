@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// merge interleaves log files by timestamp into one chronologically ordered stream, i.e.
+// logger merge api.log worker.log scheduler.log
+// Text and JSON logs can be mixed. Each file is assumed to be in order already, so lines without a recognised timestamp,
+// i.e. the continuation lines of multi-line messages, stay with the entry they follow.
+func merge(args []string) {
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	layout := flags.String("layout", "", "additional timestamp layout to recognise, as used by the time package, or a Timestamp Format preset")
+	label := flags.Bool("label", false, "prefix each entry with the name of the file it was read from")
+	colorMode := flags.String("color", "auto", "colour categories by level: auto, always or never")
+	width := flags.Int("width", 0, "minimum width of the category column")
+	var filter categoryFilter
+	filter.addFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "merge: at least one file must be provided")
+		os.Exit(2)
+	}
+	layouts := timestampLayouts
+	if *layout != "" {
+		layouts = append([]string{*layout}, layouts...)
+	}
+
+	sources := make([]*logReader, flags.NArg())
+	for i, path := range flags.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "merge: %s\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		sources[i], err = newLogReader(filepath.Base(path), f, layouts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "merge: %s: %s\n", path, err)
+			os.Exit(1)
+		}
+	}
+
+	renderer := &textRenderer{color: useColor(*colorMode), width: *width}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	var previous string
+	var previousSource *logReader
+	for {
+		// take the earliest entry at the head of a file, preferring files listed first when timestamps are equal
+		var earliest *logReader
+		for _, s := range sources {
+			if s.entry != nil && (earliest == nil || s.entry.time.Before(earliest.entry.time)) {
+				earliest = s
+			}
+		}
+		if earliest == nil {
+			return
+		}
+
+		e := earliest.entry
+		if filter.match(e.lines[0].category) {
+			// entries are only grouped under the previous entry written, which may have come from another file unless
+			// entries are labelled
+			first := &e.lines[0]
+			first.grouped = first.prefix != "" && first.category == previous && (!*label || earliest == previousSource)
+			previous, previousSource = first.category, earliest
+			for _, l := range e.lines {
+				line := renderer.render(l)
+				if *label {
+					line = earliest.name + ": " + line
+				}
+				fmt.Fprintln(w, line)
+			}
+		}
+		if err := earliest.next(); err != nil {
+			w.Flush()
+			fmt.Fprintf(os.Stderr, "merge: %s: %s\n", earliest.name, err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jemgunay/logger"
+)
+
+// pretty renders NDJSON logs written by JSONEncoder on Stdin in the aligned, coloured text format of a Logger, i.e.
+// kubectl logs my-pod | logger pretty -level warning
+// Lines which are not JSON log entries, i.e. output written before logging was configured, are passed through unchanged.
+func pretty(args []string) {
+	flags := flag.NewFlagSet("pretty", flag.ExitOnError)
+	colorMode := flags.String("color", "auto", "colour categories by level: auto, always or never")
+	width := flags.Int("width", 0, "minimum width of the category column")
+	timeFormat := flags.String("time", "01/02 15:04:05", "timestamp layout, as used by the time package")
+	utc := flags.Bool("utc", false, "render timestamps in UTC rather than local time")
+	group := flags.Bool("group", true, "omit the category of consecutive entries with the same category and level")
+	minLevel := flags.String("level", "debug", "minimum level to show")
+	var filter categoryFilter
+	filter.addFlags(flags)
+	flags.Parse(args)
+
+	min, ok := logger.ParseLevel(*minLevel)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "pretty: invalid level %q\n", *minLevel)
+		os.Exit(2)
+	}
+
+	renderer := &textRenderer{color: useColor(*colorMode), width: *width}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	var previous textLine
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+		if readErr != nil && len(raw) == 0 {
+			if readErr != io.EOF {
+				w.Flush()
+				fmt.Fprintf(os.Stderr, "pretty: %s\n", readErr)
+				os.Exit(1)
+			}
+			return
+		}
+		raw = bytes.TrimRight(raw, "\r\n")
+
+		e, err := parseJSONEntry(raw)
+		if err != nil {
+			w.Write(raw)
+			w.WriteByte('\n')
+			previous = textLine{}
+			if reader.Buffered() == 0 {
+				w.Flush()
+			}
+			continue
+		}
+		if e.Level < min || !filter.match(e.Category) {
+			continue
+		}
+
+		ts := e.Time
+		if *utc {
+			ts = ts.UTC()
+		} else {
+			ts = ts.Local()
+		}
+		lines := entryTextLines(e, ts.Format(*timeFormat))
+		lines[0].grouped = *group && e.Category != "" && previous.category == e.Category && previous.level == e.Level
+		for _, l := range lines {
+			fmt.Fprintln(w, renderer.render(l))
+		}
+		previous = lines[0]
+		// write immediately unless more input is waiting, so that live streams are not held back
+		if reader.Buffered() == 0 {
+			w.Flush()
+		}
+	}
+}
+
+// entryTextLines returns the lines of an entry in the text format of a Logger: the first line contains the timestamp,
+// the first line of the message and the fields, followed by a continuation line for each further line of the message.
+func entryTextLines(e logger.Entry, timestamp string) []textLine {
+	messageLines := strings.Split(strings.TrimRight(e.Message, "\n"), "\n")
+	first := timestamp + " " + messageLines[0]
+	if len(messageLines) == 1 && len(e.Fields) > 0 {
+		first += " " + textFields(e.Fields)
+	}
+
+	var prefix string
+	if e.Category != "" {
+		prefix = "[" + e.Category + "]"
+	}
+	lines := []textLine{{prefix: prefix, category: e.Category, level: e.Level, text: first}}
+	for i, text := range messageLines[1:] {
+		// fields follow the last line of a multi-line message, as they do in a Logger's output
+		if i == len(messageLines)-2 && len(e.Fields) > 0 {
+			text += " " + textFields(e.Fields)
+		}
+		lines = append(lines, textLine{
+			prefix:       prefix,
+			category:     e.Category,
+			level:        e.Level,
+			text:         text,
+			continuation: true,
+			indent:       len(timestamp) + 1,
+		})
+	}
+	return lines
+}
+
+// textFields formats fields as space separated key=value pairs, quoting values as a Logger does.
+func textFields(fields []logger.Field) string {
+	pairs := make([]string, len(fields))
+	for i, f := range fields {
+		value := f.String()
+		if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+			value = strconv.Quote(value)
+		}
+		pairs[i] = f.Key + "=" + value
+	}
+	return strings.Join(pairs, " ")
+}
+
+// errNotEntry is returned by parseJSONEntry for lines which are not JSON objects.
+var errNotEntry = errors.New("not a JSON log entry")
+
+// parseJSONEntry decodes a line written by JSONEncoder. Keys other than seq, time, level, category and message are
+// returned as Fields in the order they were written.
+func parseJSONEntry(line []byte) (logger.Entry, error) {
+	var e logger.Entry
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return e, errNotEntry
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		return e, err
+	}
+	found := false
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return e, err
+		}
+		key, _ := token.(string)
+
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return e, err
+		}
+		text := jsonText(value)
+		switch key {
+		case "seq":
+			e.Sequence, _ = strconv.ParseUint(text, 10, 64)
+		case "time":
+			e.Time, _ = time.Parse(time.RFC3339Nano, text)
+			found = true
+		case "level":
+			e.Level, _ = logger.ParseLevel(text)
+		case "category":
+			e.Category = text
+		case "message", "msg":
+			e.Message = text
+			found = true
+		default:
+			e.Fields = append(e.Fields, logger.String(key, text))
+		}
+	}
+	if _, err := dec.Token(); err != nil && err != io.EOF {
+		return e, err
+	}
+	if !found {
+		return e, errNotEntry
+	}
+	return e, nil
+}
+
+// jsonText returns the text of a decoded JSON value: strings and numbers as written, and anything else as JSON.
+func jsonText(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return ""
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jemgunay/logger"
+)
+
+// replay re-emits a recorded text or JSON log in real time, preserving the delays between its entries, i.e.
+// logger replay -speed 10 app.log
+// logger replay -sink otlp -endpoint http://localhost:4318 app.json
+// Entries are written to Stdout as they were recorded, or sent to a remote sink. If no file is provided, the log is
+// read from Stdin, so binary logs can be replayed with logger decode -format json app.log.bin | logger replay.
+func replay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := flags.Float64("speed", 1, "speed multiplier, i.e. 2 replays twice as fast")
+	maxDelay := flags.Duration("max-delay", 0, "longest pause between entries, skipping quiet periods (0 for no limit)")
+	sinkName := flags.String("sink", "", "send entries to a remote sink rather than stdout: otlp, otlp-grpc or datadog")
+	endpoint := flags.String("endpoint", "", "URL of the sink, defaulting to the sink's own default")
+	keepTime := flags.Bool("keep-time", false, "send entries to the sink with their recorded timestamps")
+	layout := flags.String("layout", "", "additional timestamp layout to recognise, as used by the time package, or a Timestamp Format preset")
+	flags.Parse(args)
+
+	if *speed <= 0 {
+		fmt.Fprintln(os.Stderr, "replay: -speed must be positive")
+		os.Exit(2)
+	}
+	layouts := timestampLayouts
+	if *layout != "" {
+		layouts = append([]string{*layout}, layouts...)
+	}
+
+	var in io.Reader = os.Stdin
+	name := "stdin"
+	if path := flags.Arg(0); path != "" && path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "replay: %s\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in, name = f, path
+	}
+
+	sink, err := newReplaySink(*sinkName, *endpoint)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %s\n", err)
+		os.Exit(2)
+	}
+
+	// stop replaying on interrupt, still flushing any entries queued by the sink
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = replayLog(ctx, in, name, layouts, *speed, *maxDelay, func(e *logEntry) error {
+		if sink == nil {
+			for _, line := range e.raw {
+				fmt.Println(line)
+			}
+			return nil
+		}
+		entry := e.entry
+		if !*keepTime || entry.Time.IsZero() {
+			entry.Time = time.Now()
+		}
+		return sink.WriteEntry(entry)
+	})
+	if sink != nil {
+		if closeErr := sink.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// replaySink is a remote Sink which entries can be replayed to.
+type replaySink interface {
+	logger.Sink
+	Close() error
+}
+
+// newReplaySink creates the named sink, or returns nil if no sink is named. Datadog sinks authenticate with the
+// DD_API_KEY environment variable.
+func newReplaySink(name, endpoint string) (replaySink, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "otlp", "otlp-grpc":
+		protocol, defaultEndpoint := logger.OTLPHTTP, "http://localhost:4318"
+		if name == "otlp-grpc" {
+			protocol, defaultEndpoint = logger.OTLPGRPC, "http://localhost:4317"
+		}
+		if endpoint == "" {
+			endpoint = defaultEndpoint
+		}
+		return logger.NewOTLPSink(endpoint, protocol), nil
+	case "datadog":
+		key := os.Getenv("DD_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("the datadog sink requires the DD_API_KEY environment variable")
+		}
+		sink := logger.NewDatadogSink(key)
+		if endpoint != "" {
+			sink.URL = endpoint
+		}
+		return sink, nil
+	}
+	return nil, fmt.Errorf("invalid sink %q: must be otlp, otlp-grpc or datadog", name)
+}
+
+// replayLog passes each entry of a log to emit once the time since the previous entry, divided by speed and limited to
+// maxDelay if set, has passed. Entries which are out of order or without a timestamp are emitted immediately.
+func replayLog(ctx context.Context, r io.Reader, name string, layouts []string, speed float64, maxDelay time.Duration,
+	emit func(e *logEntry) error) error {
+
+	lr, err := newLogReader(name, r, layouts)
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	// deadlines are accumulated rather than measured from each emit, so that time spent emitting does not add drift
+	deadline := time.Now()
+	var previous time.Time
+	for ; lr.entry != nil; err = lr.next() {
+		e := lr.entry
+		if !previous.IsZero() && e.time.After(previous) {
+			delay := time.Duration(float64(e.time.Sub(previous)) / speed)
+			if maxDelay > 0 && delay > maxDelay {
+				delay = maxDelay
+			}
+			deadline = deadline.Add(delay)
+			timer.Reset(time.Until(deadline))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		if !e.time.IsZero() {
+			previous = e.time
+		}
+
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// tailChunkSize is how much of a file is read at a time when searching backwards for its last lines.
+const tailChunkSize = 64 * 1024
+
+// tail follows text log files written by Loggers, i.e.
+// logger tail -n 20 -category 'ERROR,HTTP*' app.log worker.log
+// Lines are rendered with consistent padding across all of the files and coloured by Level. Files which are rotated or
+// truncated are reopened from the start.
+func tail(args []string) {
+	flags := flag.NewFlagSet("tail", flag.ExitOnError)
+	lines := flags.Int("n", 10, "number of lines to show from the end of each file")
+	follow := flags.Bool("f", true, "follow the files for new lines")
+	interval := flags.Duration("interval", 250*time.Millisecond, "how often to check the files for new lines")
+	colorMode := flags.String("color", "auto", "colour categories by level: auto, always or never")
+	width := flags.Int("width", 0, "minimum width of the category column")
+	var filter categoryFilter
+	filter.addFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "tail: at least one file must be provided")
+		os.Exit(2)
+	}
+
+	type fileLine struct {
+		file int
+		line string
+	}
+	out := make(chan fileLine)
+	done := make(chan error)
+	for i, path := range flags.Args() {
+		go func(i int, path string) {
+			done <- followFile(path, *lines, *follow, *interval, func(line string) {
+				out <- fileLine{i, line}
+			})
+		}(i, path)
+	}
+
+	// lines are rendered by a single goroutine, so that the padding is shared by every file
+	renderer := &textRenderer{color: useColor(*colorMode), width: *width}
+	previous := make([]textLine, flags.NArg())
+	w := bufio.NewWriter(os.Stdout)
+	for remaining := flags.NArg(); remaining > 0; {
+		select {
+		case fl := <-out:
+			l := parseTextLine(fl.line, previous[fl.file])
+			previous[fl.file] = l
+			if filter.match(l.category) {
+				fmt.Fprintln(w, renderer.render(l))
+			}
+			// write immediately unless more lines are waiting
+			if len(out) == 0 {
+				w.Flush()
+			}
+		case err := <-done:
+			if err != nil {
+				w.Flush()
+				fmt.Fprintf(os.Stderr, "tail: %s\n", err)
+				os.Exit(1)
+			}
+			remaining--
+		}
+	}
+	w.Flush()
+}
+
+// followFile passes the last n lines of the file at path to emit, then each new line as it is written if follow is set.
+// The file is reopened from the start if it is replaced, i.e. by log rotation, or truncated.
+func followFile(path string, n int, follow bool, interval time.Duration, emit func(string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		f.Close()
+	}()
+
+	offset, err := lastLinesOffset(f, n)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(f)
+	var partial strings.Builder
+	// readLines emits every complete line which can be read, until the end of the file or an error is reached
+	readLines := func() error {
+		for {
+			line, err := reader.ReadString('\n')
+			partial.WriteString(line)
+			offset += int64(len(line))
+			if err != nil {
+				return err
+			}
+			emit(strings.TrimRight(partial.String(), "\r\n"))
+			partial.Reset()
+		}
+	}
+
+	for {
+		if err := readLines(); err != io.EOF {
+			return err
+		}
+		if !follow {
+			if partial.Len() > 0 {
+				emit(partial.String())
+			}
+			return nil
+		}
+
+		time.Sleep(interval)
+		reopened, err := reopenIfRotated(f, path, offset)
+		if err != nil || reopened == nil {
+			// the file may be between being moved aside and recreated
+			reader.Reset(f)
+			continue
+		}
+
+		// finish reading the old file before following the new one
+		reader.Reset(f)
+		if err := readLines(); err != io.EOF {
+			reopened.Close()
+			return err
+		}
+		if partial.Len() > 0 {
+			emit(partial.String())
+			partial.Reset()
+		}
+		f.Close()
+		f = reopened
+		offset = 0
+		reader.Reset(f)
+	}
+}
+
+// reopenIfRotated opens path again if it no longer refers to the open file or has been truncated to less than offset.
+func reopenIfRotated(f *os.File, path string, offset int64) (*os.File, error) {
+	current, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	open, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if os.SameFile(current, open) && current.Size() >= offset {
+		return nil, nil
+	}
+	return os.Open(path)
+}
+
+// lastLinesOffset returns the offset of the start of the last n lines of f, searching backwards from its end.
+func lastLinesOffset(f *os.File, n int) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	end := info.Size()
+	if n <= 0 {
+		return end, nil
+	}
+
+	buf := make([]byte, tailChunkSize)
+	pos := end
+	newlines := 0
+	for pos > 0 {
+		size := int64(len(buf))
+		if pos < size {
+			size = pos
+		}
+		pos -= size
+		if _, err := f.ReadAt(buf[:size], pos); err != nil && err != io.EOF {
+			return 0, err
+		}
+		for i := size - 1; i >= 0; i-- {
+			if buf[i] != '\n' || pos+i == end-1 {
+				continue
+			}
+			// the final newline ends the last line rather than starting one
+			if newlines++; newlines == n {
+				return pos + i + 1, nil
+			}
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,33 @@
+//go:build windows || plan9
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// terminal is unsupported on this platform.
+type terminal struct {
+	resized chan os.Signal
+}
+
+func openTerminal() (*terminal, error) {
+	return nil, errors.New("the terminal viewer is not supported on this platform")
+}
+
+func (t *terminal) size() (int, int) {
+	return 80, 24
+}
+
+func (t *terminal) Read(p []byte) (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+func (t *terminal) Write(p []byte) (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+func (t *terminal) Close() error {
+	return nil
+}
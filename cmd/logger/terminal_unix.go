@@ -0,0 +1,85 @@
+//go:build !windows && !plan9
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// terminal is the controlling terminal of the process in raw mode, read from and written to directly so that Stdin
+// remains free to pipe logs in.
+type terminal struct {
+	tty *os.File
+	// state is the stty settings to restore on close.
+	state string
+	// resized receives a value each time the terminal is resized.
+	resized chan os.Signal
+}
+
+// openTerminal opens the controlling terminal and puts it into raw mode.
+func openTerminal() (*terminal, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("a terminal is required: %w", err)
+	}
+	t := &terminal{tty: tty, resized: make(chan os.Signal, 1)}
+
+	if t.state, err = t.stty("-g"); err != nil {
+		tty.Close()
+		return nil, err
+	}
+	if _, err := t.stty("raw", "-echo"); err != nil {
+		tty.Close()
+		return nil, err
+	}
+	signal.Notify(t.resized, syscall.SIGWINCH)
+	return t, nil
+}
+
+// stty runs stty against the terminal, returning its output.
+func (t *terminal) stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = t.tty
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// size returns the width and height of the terminal in characters, defaulting to 80x24.
+func (t *terminal) size() (int, int) {
+	out, err := t.stty("size")
+	if err == nil {
+		var rows, cols int
+		if _, err := fmt.Sscan(out, &rows, &cols); err == nil && rows > 0 && cols > 0 {
+			return cols, rows
+		}
+	}
+	return 80, 24
+}
+
+// Read reads key presses from the terminal.
+func (t *terminal) Read(p []byte) (int, error) {
+	return t.tty.Read(p)
+}
+
+// Write writes to the terminal.
+func (t *terminal) Write(p []byte) (int, error) {
+	return t.tty.Write(p)
+}
+
+// Close restores the terminal's original settings and closes it.
+func (t *terminal) Close() error {
+	signal.Stop(t.resized)
+	_, err := t.stty(t.state)
+	if closeErr := t.tty.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
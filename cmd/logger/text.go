@@ -0,0 +1,173 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jemgunay/logger"
+)
+
+// textLine is a line of a text log written by a Logger, split into its Category prefix and the text which follows the
+// category padding, i.e. the timestamp and message.
+type textLine struct {
+	// prefix is the Category as written, i.e. "[INFO]", and category is its Name without brackets.
+	prefix   string
+	category string
+	// level is the Level named by the Category, or LevelInfo if it does not name one.
+	level logger.Level
+	text  string
+	// column is the offset of text within the line as written.
+	column int
+	// grouped lines are entries whose Category was omitted by category grouping, and continuation lines are the
+	// indented lines of a multi-line message. Both belong to the Category of the previous line.
+	grouped      bool
+	continuation bool
+	// indent is the indent of a continuation line beyond the text of the entry it belongs to.
+	indent int
+}
+
+// parseTextLine splits a line of a text log, using the previous line of the same log to attribute lines without a
+// Category prefix. Lines which start with a digit are assumed to start with a timestamp rather than a Category.
+func parseTextLine(line string, prev textLine) textLine {
+	trimmed := strings.TrimLeft(line, " \t")
+	if trimmed == "" || len(trimmed) < len(line) {
+		l := textLine{
+			prefix:   prev.prefix,
+			category: prev.category,
+			level:    prev.level,
+			text:     trimmed,
+			column:   len(line) - len(trimmed),
+		}
+		if l.column > prev.column || trimmed == "" {
+			l.continuation = true
+			l.indent = l.column - prev.column
+			if l.indent < 0 {
+				l.indent = 0
+			}
+			l.column = prev.column
+			return l
+		}
+		l.grouped = true
+		return l
+	}
+
+	if c := trimmed[0]; c >= '0' && c <= '9' {
+		return textLine{text: line}
+	}
+	prefix := line
+	text := ""
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		prefix = line[:i]
+		text = strings.TrimLeft(line[i:], " \t")
+	}
+	category := strings.Trim(prefix, "[](){}<>")
+	level, _ := logger.ParseLevel(category)
+	return textLine{
+		prefix:   prefix,
+		category: category,
+		level:    level,
+		text:     text,
+		column:   len(line) - len(text),
+	}
+}
+
+// textRenderer renders parsed lines with consistent category padding and optional colour, grouping lines of the same
+// Category as a Logger would.
+type textRenderer struct {
+	color bool
+	// width is the widest Category prefix rendered so far, or a fixed width.
+	width int
+}
+
+// render returns a parsed line as it should be displayed.
+func (r *textRenderer) render(l textLine) string {
+	if len(l.prefix) > r.width {
+		r.width = len(l.prefix)
+	}
+	switch {
+	case l.continuation:
+		if l.text == "" {
+			return ""
+		}
+		return strings.Repeat(" ", r.width+1+l.indent) + l.text
+	case l.grouped:
+		return strings.Repeat(" ", r.width+1) + l.text
+	case l.prefix == "":
+		return l.text
+	}
+
+	prefix := l.prefix
+	if r.color {
+		prefix = l.level.Color() + prefix + "\x1b[0m"
+	}
+	if l.text == "" {
+		return prefix
+	}
+	return prefix + strings.Repeat(" ", r.width-len(l.prefix)+1) + l.text
+}
+
+// categoryFilter selects lines by their Category Name, using glob patterns as SetEnabledByCategory does.
+type categoryFilter struct {
+	include []string
+	exclude []string
+}
+
+// addFlags registers the -category and -exclude flags.
+func (f *categoryFilter) addFlags(flags *flag.FlagSet) {
+	flags.Var((*patternList)(&f.include), "category", "only show categories matching these comma separated patterns")
+	flags.Var((*patternList)(&f.exclude), "exclude", "hide categories matching these comma separated patterns")
+}
+
+// match reports whether lines of the Category should be shown. Lines without a Category are shown unless categories
+// have been included explicitly.
+func (f *categoryFilter) match(category string) bool {
+	if len(f.include) > 0 && !matchAny(f.include, category) {
+		return false
+	}
+	return !matchAny(f.exclude, category)
+}
+
+// matchAny reports whether name matches one of the glob patterns, ignoring case.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// patternList is a flag.Value of comma separated patterns, which may be repeated.
+type patternList []string
+
+func (p *patternList) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *patternList) Set(value string) error {
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			*p = append(*p, pattern)
+		}
+	}
+	return nil
+}
+
+// useColor resolves the -color flag: "always", "never" or "auto", which colours output written to a terminal.
+func useColor(mode string) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	case "auto":
+		info, err := os.Stdout.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0 && os.Getenv("NO_COLOR") == ""
+	}
+	fmt.Fprintf(os.Stderr, "invalid -color %q: must be auto, always or never\n", mode)
+	os.Exit(2)
+	return false
+}
@@ -0,0 +1,657 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jemgunay/logger"
+)
+
+// viewRedrawInterval limits how often the viewer redraws while lines are arriving.
+const viewRedrawInterval = 50 * time.Millisecond
+
+// viewHelp is shown by the viewer's help screen.
+var viewHelp = []string{
+	"j, k, up, down        scroll by a line",
+	"space, b, pgdn, pgup  scroll by a page",
+	"g, G, home, end       go to the top or bottom, G and end resuming follow",
+	"f                     pause or resume following new entries",
+	"/                     search, then n and N for the next and previous match",
+	"+, -                  raise or lower the minimum level",
+	"c                     show or hide categories",
+	"q                     quit",
+}
+
+// viewEntry is an entry held by the viewer, with the lines it was written as.
+type viewEntry struct {
+	category string
+	level    logger.Level
+	lines    []textLine
+}
+
+// viewMode is the screen the viewer is showing.
+type viewMode int
+
+const (
+	viewLog viewMode = iota
+	viewSearch
+	viewCategories
+	viewHelpScreen
+)
+
+// viewer is an interactive terminal view of a text or JSON log, holding the scrollback and the current filters.
+type viewer struct {
+	color      bool
+	maxEntries int
+	renderer   textRenderer
+	entries    []*viewEntry
+	prev       textLine
+
+	// categories are the Category Names seen so far, in the order they were first seen.
+	categories []string
+	hidden     map[string]bool
+	minLevel   logger.Level
+
+	follow bool
+	// offset is the index of the first line shown, amongst the lines of the entries which pass the filters.
+	offset int
+	query  string
+
+	mode    viewMode
+	input   string
+	cursor  int
+	message string
+
+	width, height int
+}
+
+// view is an interactive terminal viewer for text and JSON logs with scrollback, live follow, category toggles, level
+// filters and search, for environments where the web viewer cannot be reached, i.e.
+// logger view app.log
+// kubectl logs -f my-pod | logger view
+func view(args []string) {
+	flags := flag.NewFlagSet("view", flag.ExitOnError)
+	lines := flags.Int("n", 10000, "number of lines to load from the end of the file")
+	maxEntries := flags.Int("max", 100000, "number of entries to keep in the scrollback")
+	interval := flags.Duration("interval", 250*time.Millisecond, "how often to check the file for new lines")
+	colorMode := flags.String("color", "auto", "colour categories by level: auto, always or never")
+	minLevel := flags.String("level", "debug", "minimum level to show")
+	flags.Parse(args)
+
+	lvl, ok := logger.ParseLevel(*minLevel)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "view: invalid level %q\n", *minLevel)
+		os.Exit(2)
+	}
+	if flags.NArg() == 0 {
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			fmt.Fprintln(os.Stderr, "view: a file or piped input must be provided")
+			os.Exit(2)
+		}
+	}
+
+	term, err := openTerminal()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "view: %s\n", err)
+		os.Exit(1)
+	}
+	v := &viewer{
+		color:      useColor(*colorMode),
+		maxEntries: *maxEntries,
+		hidden:     make(map[string]bool),
+		minLevel:   lvl,
+		follow:     true,
+	}
+	v.width, v.height = term.size()
+
+	received := make(chan string, 1024)
+	done := make(chan error, 1)
+	go func() {
+		emit := func(line string) {
+			received <- line
+		}
+		if flags.NArg() == 0 {
+			done <- readLines(os.Stdin, emit)
+			return
+		}
+		done <- followFile(flags.Arg(0), *lines, true, *interval, emit)
+	}()
+
+	err = v.run(term, received, done)
+	if closeErr := term.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "view: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// readLines passes each line read from r to emit.
+func readLines(r io.Reader, emit func(string)) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			emit(strings.TrimRight(line, "\r\n"))
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// run draws the viewer on the terminal, adding lines as they are received and handling key presses until it is quit.
+func (v *viewer) run(term *terminal, received <-chan string, done <-chan error) error {
+	out := bufio.NewWriterSize(term, 64*1024)
+	// switch to the alternate screen and hide the cursor, restoring both on exit
+	out.WriteString("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		out.WriteString("\x1b[?25h\x1b[?1049l")
+		out.Flush()
+	}()
+
+	keys := make(chan []string)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := term.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- parseKeys(buf[:n])
+		}
+	}()
+
+	ticker := time.NewTicker(viewRedrawInterval)
+	defer ticker.Stop()
+	dirty := true
+	for {
+		select {
+		case line := <-received:
+			v.addLine(line)
+			dirty = true
+		case pressed, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, key := range pressed {
+				if v.handleKey(key) {
+					return nil
+				}
+			}
+			v.draw(out)
+			dirty = false
+		case <-term.resized:
+			v.width, v.height = term.size()
+			dirty = true
+		case err := <-done:
+			v.message = "end of input"
+			if err != nil {
+				v.message = err.Error()
+			}
+			dirty = true
+		case <-ticker.C:
+			if dirty {
+				v.draw(out)
+				dirty = false
+			}
+		}
+	}
+}
+
+// addLine adds a line of a text or JSON log to the scrollback, as a new entry or as a continuation of the last one.
+func (v *viewer) addLine(raw string) {
+	raw = strings.ReplaceAll(raw, "\t", "    ")
+	var e *viewEntry
+	if parsed, err := parseJSONEntry([]byte(raw)); err == nil {
+		lines := entryTextLines(parsed, parsed.Time.Local().Format(timestampLayouts[0]))
+		e = &viewEntry{category: parsed.Category, level: parsed.Level, lines: lines}
+		v.prev = textLine{}
+	} else {
+		l := parseTextLine(raw, v.prev)
+		v.prev = l
+		if l.continuation && len(v.entries) > 0 {
+			last := v.entries[len(v.entries)-1]
+			last.lines = append(last.lines, l)
+			return
+		}
+		e = &viewEntry{category: l.category, level: l.level, lines: []textLine{l}}
+	}
+
+	if e.category != "" && !v.seen(e.category) {
+		v.categories = append(v.categories, e.category)
+	}
+	v.entries = append(v.entries, e)
+
+	// drop the oldest entries, keeping the lines shown in place while paused
+	if excess := len(v.entries) - v.maxEntries; v.maxEntries > 0 && excess > 0 {
+		for _, old := range v.entries[:excess] {
+			if v.visible(old) {
+				v.offset -= len(old.lines)
+			}
+		}
+		if v.offset < 0 {
+			v.offset = 0
+		}
+		v.entries = append([]*viewEntry(nil), v.entries[excess:]...)
+	}
+}
+
+// seen reports whether the Category has been seen before.
+func (v *viewer) seen(category string) bool {
+	for _, c := range v.categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// visible reports whether an entry passes the category and level filters.
+func (v *viewer) visible(e *viewEntry) bool {
+	return !v.hidden[e.category] && e.level >= v.minLevel
+}
+
+// lines returns the lines of the entries which pass the filters. Entries are grouped under the previous entry shown,
+// rather than the previous entry written, as it may have been filtered out.
+func (v *viewer) lines() []textLine {
+	var lines []textLine
+	previous := ""
+	for _, e := range v.entries {
+		if !v.visible(e) {
+			continue
+		}
+		first := len(lines)
+		lines = append(lines, e.lines...)
+		lines[first].grouped = lines[first].prefix != "" && e.category == previous
+		previous = e.category
+	}
+	return lines
+}
+
+// matches reports whether a line contains the search query, ignoring case.
+func (v *viewer) matches(l textLine) bool {
+	if v.query == "" {
+		return false
+	}
+	query := strings.ToLower(v.query)
+	return strings.Contains(strings.ToLower(l.text), query) || strings.Contains(strings.ToLower(l.prefix), query)
+}
+
+// page returns the number of log lines which fit on the screen above the status line.
+func (v *viewer) page() int {
+	if v.height < 2 {
+		return 1
+	}
+	return v.height - 1
+}
+
+// handleKey applies a key press, reporting whether the viewer should quit.
+func (v *viewer) handleKey(key string) bool {
+	v.message = ""
+	switch v.mode {
+	case viewSearch:
+		switch key {
+		case "enter":
+			v.mode = viewLog
+			v.query = v.input
+			v.find(v.offset, 1)
+		case "esc", "ctrl-c":
+			v.mode = viewLog
+		case "backspace":
+			if _, size := utf8.DecodeLastRuneInString(v.input); size > 0 {
+				v.input = v.input[:len(v.input)-size]
+			}
+		default:
+			if utf8.RuneCountInString(key) == 1 {
+				v.input += key
+			}
+		}
+		return false
+
+	case viewCategories:
+		switch key {
+		case "j", "down":
+			if v.cursor < len(v.categories)-1 {
+				v.cursor++
+			}
+		case "k", "up":
+			if v.cursor > 0 {
+				v.cursor--
+			}
+		case " ", "enter":
+			if v.cursor < len(v.categories) {
+				name := v.categories[v.cursor]
+				v.hidden[name] = !v.hidden[name]
+			}
+		case "a":
+			v.hidden = make(map[string]bool)
+		case "c", "esc", "q":
+			v.mode = viewLog
+		case "ctrl-c":
+			return true
+		}
+		return false
+
+	case viewHelpScreen:
+		v.mode = viewLog
+		return key == "ctrl-c"
+	}
+
+	maxOffset := len(v.lines()) - v.page()
+	if v.follow {
+		v.offset = maxOffset
+	}
+	switch key {
+	case "q", "ctrl-c":
+		return true
+	case "j", "down":
+		v.offset++
+	case "k", "up":
+		v.offset--
+		v.follow = false
+	case " ", "pgdn", "ctrl-f":
+		v.offset += v.page()
+	case "b", "pgup", "ctrl-b":
+		v.offset -= v.page()
+		v.follow = false
+	case "g", "home":
+		v.offset = 0
+		v.follow = false
+	case "G", "end":
+		v.follow = true
+	case "f":
+		v.follow = !v.follow
+	case "/":
+		v.mode = viewSearch
+		v.input = ""
+	case "n":
+		v.find(v.offset+1, 1)
+	case "N":
+		v.find(v.offset-1, -1)
+	case "+":
+		if v.minLevel < logger.LevelFatal {
+			v.minLevel++
+		}
+	case "-":
+		if v.minLevel > logger.LevelDebug {
+			v.minLevel--
+		}
+	case "c":
+		v.mode = viewCategories
+	case "?":
+		v.mode = viewHelpScreen
+	}
+	if v.offset > maxOffset {
+		v.offset = maxOffset
+	}
+	if v.offset < 0 {
+		v.offset = 0
+	}
+	return false
+}
+
+// find moves to the next line matching the query from start in the direction of step, wrapping around the scrollback.
+func (v *viewer) find(start, step int) {
+	if v.query == "" {
+		return
+	}
+	lines := v.lines()
+	for i := 0; i < len(lines); i++ {
+		index := ((start+i*step)%len(lines) + len(lines)) % len(lines)
+		if v.matches(lines[index]) {
+			v.offset = index
+			v.follow = false
+			if (step > 0 && index < start) || (step < 0 && index > start) {
+				v.message = "search wrapped"
+			}
+			return
+		}
+	}
+	v.message = "pattern not found: " + v.query
+}
+
+// draw redraws the whole screen.
+func (v *viewer) draw(out *bufio.Writer) {
+	var rows []string
+	var status string
+	switch v.mode {
+	case viewCategories:
+		rows = append(rows, "space: show/hide   a: show all   c: close")
+		for i, name := range v.categories {
+			mark := "[x]"
+			if v.hidden[name] {
+				mark = "[ ]"
+			}
+			row := mark + " " + name
+			if i == v.cursor {
+				row = "\x1b[7m" + row + "\x1b[0m"
+			}
+			rows = append(rows, row)
+		}
+		status = fmt.Sprintf(" categories: %d shown, %d hidden", len(v.categories)-v.hiddenCount(), v.hiddenCount())
+
+	case viewHelpScreen:
+		rows = append(rows, viewHelp...)
+		status = " press any key to return"
+
+	default:
+		lines := v.lines()
+		page := v.page()
+		if v.follow {
+			v.offset = len(lines) - page
+		}
+		if v.offset > len(lines)-page {
+			v.offset = len(lines) - page
+		}
+		if v.offset < 0 {
+			v.offset = 0
+		}
+		end := v.offset + page
+		if end > len(lines) {
+			end = len(lines)
+		}
+		matches := 0
+		for _, l := range lines {
+			if v.matches(l) {
+				matches++
+			}
+			// render every line, so that the category padding accounts for lines which are not on screen
+			v.renderer.render(l)
+		}
+		for _, l := range lines[v.offset:end] {
+			rows = append(rows, v.style(l))
+		}
+		status = v.status(len(lines), end, matches)
+	}
+
+	out.WriteString("\x1b[H")
+	for i := 0; i < v.page(); i++ {
+		if i < len(rows) {
+			out.WriteString(rows[i])
+		}
+		out.WriteString("\x1b[K\r\n")
+	}
+	out.WriteString("\x1b[7m" + padRight(truncate(status, v.width), v.width) + "\x1b[0m")
+	out.Flush()
+}
+
+// hiddenCount returns the number of hidden categories.
+func (v *viewer) hiddenCount() int {
+	n := 0
+	for _, hidden := range v.hidden {
+		if hidden {
+			n++
+		}
+	}
+	return n
+}
+
+// status returns the text of the status line while showing the log.
+func (v *viewer) status(total, end, matches int) string {
+	if v.mode == viewSearch {
+		return "/" + v.input
+	}
+	state := "FOLLOW"
+	if !v.follow {
+		state = "PAUSED"
+	}
+	parts := []string{
+		" " + state,
+		fmt.Sprintf("lines %d-%d of %d", min(v.offset+1, total), end, total),
+		"level >= " + v.minLevel.String(),
+	}
+	var hidden []string
+	for _, name := range v.categories {
+		if v.hidden[name] {
+			hidden = append(hidden, name)
+		}
+	}
+	if len(hidden) > 0 {
+		parts = append(parts, "hidden: "+strings.Join(hidden, ","))
+	}
+	if v.query != "" {
+		parts = append(parts, fmt.Sprintf("/%s: %d matches", v.query, matches))
+	}
+	if v.message != "" {
+		parts = append(parts, v.message)
+	}
+	parts = append(parts, "? help")
+	return strings.Join(parts, " | ")
+}
+
+// style renders a line for the screen, truncated to its width, colouring the Category by Level and highlighting
+// search matches.
+func (v *viewer) style(l textLine) string {
+	plain := truncate(v.renderer.render(l), v.width)
+
+	prefixEnd := 0
+	if v.color && l.prefix != "" && !l.grouped && !l.continuation && strings.HasPrefix(plain, l.prefix) {
+		prefixEnd = len(l.prefix)
+	}
+	highlighted := make([]bool, len(plain))
+	if lower := strings.ToLower(plain); v.query != "" && len(lower) == len(plain) {
+		query := strings.ToLower(v.query)
+		for i := 0; ; {
+			j := strings.Index(lower[i:], query)
+			if j < 0 {
+				break
+			}
+			for k := i + j; k < i+j+len(query); k++ {
+				highlighted[k] = true
+			}
+			i += j + len(query)
+		}
+	}
+
+	var b strings.Builder
+	inPrefix, inMatch := false, false
+	for i := 0; i < len(plain); i++ {
+		prefix, match := i < prefixEnd, highlighted[i]
+		if prefix != inPrefix || match != inMatch {
+			if inPrefix || inMatch {
+				b.WriteString("\x1b[0m")
+			}
+			if prefix {
+				b.WriteString(l.level.Color())
+			}
+			if match {
+				b.WriteString("\x1b[7m")
+			}
+			inPrefix, inMatch = prefix, match
+		}
+		b.WriteByte(plain[i])
+	}
+	if inPrefix || inMatch {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// truncate shortens s to at most width characters.
+func truncate(s string, width int) string {
+	n := 0
+	for i := range s {
+		if n == width {
+			return s[:i]
+		}
+		n++
+	}
+	return s
+}
+
+// padRight pads s with spaces to width characters.
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// escapeKeys names the keys sent as escape sequences, by the sequence which follows "ESC [" or "ESC O".
+var escapeKeys = map[string]string{
+	"A":  "up",
+	"B":  "down",
+	"C":  "right",
+	"D":  "left",
+	"H":  "home",
+	"F":  "end",
+	"1~": "home",
+	"4~": "end",
+	"5~": "pgup",
+	"6~": "pgdn",
+}
+
+// parseKeys splits input read from a terminal in raw mode into key names: a character, or the name of a special key.
+func parseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		switch b[0] {
+		case 0x1b:
+			if len(b) > 2 && (b[1] == '[' || b[1] == 'O') {
+				// escape sequences end with a byte in the range @ to ~
+				end := 2
+				for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+					end++
+				}
+				if end == len(b) {
+					return keys
+				}
+				if name, ok := escapeKeys[string(b[2:end+1])]; ok {
+					keys = append(keys, name)
+				}
+				b = b[end+1:]
+				continue
+			}
+			keys = append(keys, "esc")
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			keys = append(keys, "ctrl-c")
+		case 0x06:
+			keys = append(keys, "ctrl-f")
+		case 0x02:
+			keys = append(keys, "ctrl-b")
+		default:
+			r, size := utf8.DecodeRune(b)
+			keys = append(keys, string(r))
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
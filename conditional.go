@@ -0,0 +1,46 @@
+package logger
+
+// ErrorKey is the key of the Field holding the error logged by LogErr and LogErrf.
+const ErrorKey = "error"
+
+// LogIf logs the provided message if cond is true and the Logger is enabled.
+func (l *Logger) LogIf(cond bool, msg ...interface{}) {
+	if cond {
+		l.performLazyLog(lazySprint(msg), false, nil)
+	}
+}
+
+// LogfIf logs the provided message with formatting if cond is true and the Logger is enabled.
+func (l *Logger) LogfIf(cond bool, format string, args ...interface{}) {
+	if cond {
+		l.performLazyLog(lazySprintf(format, args), false, nil)
+	}
+}
+
+// LogErr logs the provided message with err attached as an "error" Field if err is not nil, replacing boilerplate such
+// as:
+//
+//	if err != nil {
+//		Error.Logf("failed to close connection: %s", err)
+//	}
+//
+// with Error.LogErr(err, "failed to close connection"). If no message is provided, the error text is logged as the
+// message instead.
+func (l *Logger) LogErr(err error, msg ...interface{}) {
+	if err == nil {
+		return
+	}
+	if len(msg) == 0 {
+		l.performLog(err.Error(), false, nil)
+		return
+	}
+	l.performLazyLog(lazySprint(msg), false, []Field{String(ErrorKey, err.Error())})
+}
+
+// LogErrf logs the provided message with formatting and err attached as an "error" Field if err is not nil.
+func (l *Logger) LogErrf(err error, format string, args ...interface{}) {
+	if err == nil {
+		return
+	}
+	l.performLazyLog(lazySprintf(format, args), false, []Field{String(ErrorKey, err.Error())})
+}
@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Config declares the loggers of a program and how they are written, so that logging behaviour can be changed without
+// recompiling. It is usually loaded from a file with Configure. Unset package options are left unchanged.
+type Config struct {
+	Padding         *bool `json:"padding,omitempty"`
+	Grouping        *bool `json:"grouping,omitempty"`
+	MultilineIndent *bool `json:"multiline_indent,omitempty"`
+	LineWrapping    *bool `json:"line_wrapping,omitempty"`
+	Buffered        *bool `json:"buffered,omitempty"`
+	// CategoryWidth is a fixed category column width as set by SetCategoryWidth, where 0 restores dynamic padding.
+	CategoryWidth *int `json:"category_width,omitempty"`
+	// Groups are category groups to define with DefineCategoryGroup, keyed by group name.
+	Groups  map[string][]string `json:"groups,omitempty"`
+	Loggers []LoggerConfig      `json:"loggers"`
+}
+
+// LoggerConfig declares a single Logger, identified by its Category Name.
+type LoggerConfig struct {
+	Category string `json:"category"`
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Level is a name accepted by ParseLevel. It defaults to the Level named by the Category, as with NewLogger.
+	Level string `json:"level,omitempty"`
+	// Timestamp is the Timestamp Format, or the name of a preset: rfc3339, rfc3339nano, unix, unix_milli, unix_nano or
+	// elapsed. It defaults to the format used by NewLogger.
+	Timestamp      string `json:"timestamp,omitempty"`
+	UTC            bool   `json:"utc,omitempty"`
+	MaxMessageSize int    `json:"max_message_size,omitempty"`
+	Sequence       bool   `json:"sequence,omitempty"`
+	// Delta writes the time since the Logger's previous message after the timestamp.
+	Delta bool `json:"delta,omitempty"`
+	// Tags replace the tags of the Logger, for use with SetEnabledByTag.
+	Tags []string `json:"tags,omitempty"`
+	// Outputs are the destinations the Logger writes to. A Logger without outputs writes to Stdout.
+	Outputs []OutputConfig `json:"outputs,omitempty"`
+}
+
+// OutputConfig declares a destination which a Logger writes to.
+type OutputConfig struct {
+	// Path is "stdout", "stderr", "discard" or the path of a file to append to.
+	Path string `json:"path"`
+	// Format is "text" (the Logger's own format, the default), "color" or "json".
+	Format string `json:"format,omitempty"`
+	// Level is the minimum Level written to the output. All Levels are written by default.
+	Level string `json:"level,omitempty"`
+	// MaxSize is the size in bytes at which a file is rotated, as described by RotationPolicy. Files are not rotated by
+	// default. Outputs with the same path share a file, so should declare the same rotation.
+	MaxSize int64 `json:"max_size,omitempty"`
+	// MaxBackups is the number of rotated files to keep.
+	MaxBackups int `json:"max_backups,omitempty"`
+}
+
+var (
+	configFormats = map[string]func([]byte, interface{}) error{
+		".json": json.Unmarshal,
+	}
+	configMu sync.Mutex
+	// configPath is the file most recently loaded by Configure, which ReloadConfig loads again.
+	configPath string
+	// configFiles are the files opened for outputs by Configure, keyed by path.
+	configFiles = make(map[string]*File)
+)
+
+// RegisterConfigFormat registers the function used by Configure to decode config files with the provided extension.
+// Only JSON is supported by default, so that the package has no dependencies, and YAML and TOML are registered by
+// importing the configformat package:
+//
+//	import _ "github.com/jemgunay/logger/configformat"
+//
+// The fields of Config are named by their json tags, so other formats need a decoder which honours them.
+func RegisterConfigFormat(ext string, unmarshal func(data []byte, v interface{}) error) {
+	configMu.Lock()
+	configFormats[strings.ToLower(ext)] = unmarshal
+	configMu.Unlock()
+}
+
+// Configure loads the config file at path, decoding it according to its extension, and applies it with ApplyConfig.
+// The configured loggers are returned keyed by Category Name.
+func Configure(path string) (map[string]*Logger, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	configMu.Lock()
+	unmarshal, ok := configFormats[ext]
+	configMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported config format %q, see RegisterConfigFormat", ext)
+	}
+
+	var cfg Config
+	if err := unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config %s: %s", path, err)
+	}
+	configured, err := ApplyConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	configMu.Lock()
+	configPath = path
+	configMu.Unlock()
+	return configured, nil
+}
+
+// ApplyConfig applies the package options of cfg and configures each of its loggers. Loggers which already exist with
+// the same Category Name are reconfigured in place, and all other loggers are created and registered. The configured
+// loggers are returned keyed by Category Name.
+func ApplyConfig(cfg Config) (map[string]*Logger, error) {
+	// validate and open everything before changing any logger, so that an invalid config has no effect
+	type pending struct {
+		cfg    LoggerConfig
+		level  Level
+		writer io.Writer
+	}
+	var loggerConfigs []pending
+	for _, lc := range cfg.Loggers {
+		p := pending{cfg: lc, level: levelFromCategory(lc.Category)}
+		if lc.Level != "" {
+			lvl, ok := ParseLevel(lc.Level)
+			if !ok {
+				return nil, fmt.Errorf("logger %s has an invalid level %q", lc.Category, lc.Level)
+			}
+			p.level = lvl
+		}
+		w, err := configWriter(lc.Outputs)
+		if err != nil {
+			return nil, fmt.Errorf("logger %s: %s", lc.Category, err)
+		}
+		p.writer = w
+		loggerConfigs = append(loggerConfigs, p)
+	}
+
+	if cfg.Grouping != nil {
+		SetCategoryGrouping(*cfg.Grouping)
+	}
+	if cfg.CategoryWidth != nil {
+		SetCategoryWidth(*cfg.CategoryWidth)
+	}
+	if cfg.MultilineIndent != nil {
+		SetMultilineIndent(*cfg.MultilineIndent)
+	}
+	if cfg.LineWrapping != nil {
+		SetLineWrapping(*cfg.LineWrapping)
+	}
+	if cfg.Buffered != nil {
+		SetBuffered(*cfg.Buffered)
+	}
+	for name, categories := range cfg.Groups {
+		DefineCategoryGroup(name, categories...)
+	}
+
+	existing := make(map[string]*Logger)
+	for _, l := range registeredLoggers() {
+		if _, ok := existing[l.Category.Name]; !ok {
+			existing[l.Category.Name] = l
+		}
+	}
+
+	configured := make(map[string]*Logger, len(loggerConfigs))
+	for _, p := range loggerConfigs {
+		enabled := p.cfg.Enabled == nil || *p.cfg.Enabled
+		l, ok := existing[p.cfg.Category]
+		if !ok {
+			l = NewLogger(p.writer, p.cfg.Category, enabled)
+		}
+		format := p.cfg.Timestamp
+		if preset, ok := timestampPresets[strings.ToLower(format)]; ok {
+			format = preset
+		}
+		if format == "" {
+			format = "01/02 15:04:05"
+		}
+
+		// loggers which are already in use are changed while other goroutines may be logging with them
+		l.stateMu.Lock()
+		l.Writer = p.writer
+		l.Enabled = enabled
+		l.Level = p.level
+		l.Timestamp.Format = format
+		l.Timestamp.UseUTC = p.cfg.UTC
+		l.Timestamp.Delta = p.cfg.Delta
+		l.maxMessageSize = p.cfg.MaxMessageSize
+		l.sequenceField = p.cfg.Sequence
+		l.tags = appendTags(nil, p.cfg.Tags)
+		l.stateMu.Unlock()
+		configured[p.cfg.Category] = l
+	}
+
+	if cfg.Padding != nil {
+		SetCategoryPadding(*cfg.Padding)
+	} else {
+		updateCategoryPadding()
+	}
+	closeUnusedConfigFiles()
+	return configured, nil
+}
+
+// configWriter creates the Writer for a Logger's outputs.
+func configWriter(outputs []OutputConfig) (io.Writer, error) {
+	if len(outputs) == 0 {
+		return os.Stdout, nil
+	}
+	if len(outputs) == 1 && outputs[0].Format == "" && outputs[0].Level == "" {
+		return configOutput(outputs[0])
+	}
+
+	multi := MultiWriter()
+	for _, o := range outputs {
+		w, err := configOutput(o)
+		if err != nil {
+			return nil, err
+		}
+
+		encoder, ok := ParseEncoder(o.Format)
+		if !ok {
+			return nil, fmt.Errorf("output %s has an invalid format %q", o.Path, o.Format)
+		}
+
+		minLevel := LevelDebug
+		if o.Level != "" {
+			lvl, ok := ParseLevel(o.Level)
+			if !ok {
+				return nil, fmt.Errorf("output %s has an invalid level %q", o.Path, o.Level)
+			}
+			minLevel = lvl
+		}
+		multi.AddEncoded(w, encoder, minLevel)
+	}
+	return multi, nil
+}
+
+// configOutput returns the Writer for an output, opening files for appending and applying their rotation. Files are
+// shared between all outputs with the same path.
+func configOutput(o OutputConfig) (io.Writer, error) {
+	if o.MaxSize < 0 || o.MaxBackups < 0 {
+		return nil, fmt.Errorf("output %s has a negative rotation", o.Path)
+	}
+	rotated := o.MaxSize > 0 || o.MaxBackups > 0
+
+	var w io.Writer
+	switch strings.ToLower(o.Path) {
+	case "", "stdout":
+		w = os.Stdout
+	case "stderr":
+		w = os.Stderr
+	case "discard":
+		w = ioutil.Discard
+	}
+	if w != nil {
+		if rotated {
+			return nil, fmt.Errorf("output %s cannot be rotated", o.Path)
+		}
+		return w, nil
+	}
+
+	configMu.Lock()
+	defer configMu.Unlock()
+	f, ok := configFiles[o.Path]
+	if !ok {
+		var err error
+		if f, err = OpenFile(o.Path, 0644); err != nil {
+			return nil, err
+		}
+		configFiles[o.Path] = f
+	}
+	f.SetRotation(RotationPolicy{MaxSize: o.MaxSize, MaxBackups: o.MaxBackups})
+	return f, nil
+}
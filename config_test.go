@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigureWritesToOutputs(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	configPath := filepath.Join(dir, "logging.json")
+	config := `{"loggers": [{
+		"category": "CONFIGURED",
+		"level": "warning",
+		"timestamp": "unix",
+		"tags": ["db"],
+		"outputs": [{"path": ` + "\"" + logPath + "\"" + `}]
+	}]}`
+	if err := ioutil.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loggers, err := Configure(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := loggers["CONFIGURED"]
+	defer RemoveLogger(l)
+	if l.CurrentLevel() != LevelWarning || !l.HasTag("db") {
+		t.Errorf("the logger was not configured: level %s, tags %v", l.CurrentLevel(), l.Tags())
+	}
+
+	l.Log("configured message")
+	Flush(time.Second)
+	data, err := ioutil.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "configured message") {
+		t.Errorf("expected the message to be written to the configured file, got %q", data)
+	}
+
+	// applying a config again reconfigures the registered logger rather than creating another
+	reconfigured, err := ApplyConfig(Config{Loggers: []LoggerConfig{{Category: "CONFIGURED", Level: "error"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reconfigured["CONFIGURED"] != l || l.CurrentLevel() != LevelError {
+		t.Error("expected the existing logger to be reconfigured in place")
+	}
+}
+
+func TestApplyConfigRejectsInvalidConfig(t *testing.T) {
+	l := NewLogger(ioutil.Discard, "UNCHANGED", true)
+	l.SetLevel(LevelInfo)
+	defer RemoveLogger(l)
+
+	configs := map[string]Config{
+		"level": {Loggers: []LoggerConfig{{Category: "UNCHANGED", Level: "loud"}}},
+		"format": {Loggers: []LoggerConfig{{
+			Category: "UNCHANGED",
+			Outputs:  []OutputConfig{{Path: "discard", Format: "xml"}},
+		}}},
+		"output level": {Loggers: []LoggerConfig{{
+			Category: "UNCHANGED",
+			Outputs:  []OutputConfig{{Path: "discard", Level: "loud"}},
+		}}},
+		"rotation": {Loggers: []LoggerConfig{{
+			Category: "UNCHANGED",
+			Outputs:  []OutputConfig{{Path: filepath.Join(t.TempDir(), "app.log"), MaxSize: -1}},
+		}}},
+	}
+	for name, cfg := range configs {
+		t.Run(name, func(t *testing.T) {
+			// a valid logger before the invalid one must not be applied either
+			cfg.Loggers = append([]LoggerConfig{{Category: "UNCHANGED", Level: "error"}}, cfg.Loggers...)
+			if _, err := ApplyConfig(cfg); err == nil {
+				t.Fatal("expected the config to be rejected")
+			}
+			if l.CurrentLevel() != LevelInfo {
+				t.Errorf("an invalid config changed the logger's level to %s", l.CurrentLevel())
+			}
+		})
+	}
+}
+
+func TestConfigureUnsupportedFormat(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "logging.ini")
+	if err := ioutil.WriteFile(configPath, []byte("[loggers]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Configure(configPath); err == nil || !strings.Contains(err.Error(), "unsupported config format") {
+		t.Errorf("expected an unsupported format error, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+// Package configformat adds YAML and TOML support to the logger package's config files. Importing the package
+// registers decoders for the .yaml, .yml and .toml extensions with RegisterConfigFormat, so that Configure and
+// StartConfigWatcher accept them:
+//
+//	import _ "github.com/jemgunay/logger/configformat"
+//
+// The keys of every format are the json field names of logger.Config, i.e. "max_message_size".
+package configformat
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jemgunay/logger"
+	"go.yaml.in/yaml/v3"
+)
+
+func init() {
+	logger.RegisterConfigFormat(".yaml", UnmarshalYAML)
+	logger.RegisterConfigFormat(".yml", UnmarshalYAML)
+	logger.RegisterConfigFormat(".toml", UnmarshalTOML)
+}
+
+// UnmarshalYAML decodes a YAML document into v, honouring the json field names of v.
+func UnmarshalYAML(data []byte, v interface{}) error {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return viaJSON(doc, v)
+}
+
+// UnmarshalTOML decodes a TOML document into v, honouring the json field names of v.
+func UnmarshalTOML(data []byte, v interface{}) error {
+	var doc map[string]interface{}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	return viaJSON(doc, v)
+}
+
+// viaJSON decodes a generic document into v by encoding it as JSON, so that the fields of v are matched by their json
+// tags.
+func viaJSON(doc interface{}, v interface{}) error {
+	normalised, err := normalise(doc)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(normalised)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// normalise converts the maps of a decoded document into maps with string keys, which can be encoded as JSON. YAML
+// mappings may have keys of any type, which are only accepted if they are strings.
+func normalise(doc interface{}) (interface{}, error) {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		for k, v := range doc {
+			n, err := normalise(v)
+			if err != nil {
+				return nil, err
+			}
+			doc[k] = n
+		}
+		return doc, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(doc))
+		for k, v := range doc {
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("key %v is not a string", k)
+			}
+			n, err := normalise(v)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = n
+		}
+		return m, nil
+	case []interface{}:
+		for i, v := range doc {
+			n, err := normalise(v)
+			if err != nil {
+				return nil, err
+			}
+			doc[i] = n
+		}
+		return doc, nil
+	case []map[string]interface{}:
+		for _, m := range doc {
+			if _, err := normalise(m); err != nil {
+				return nil, err
+			}
+		}
+		return doc, nil
+	}
+	return doc, nil
+}
@@ -0,0 +1,109 @@
+package configformat
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jemgunay/logger"
+)
+
+const yamlConfig = `
+padding: false
+category_width: 12
+groups:
+  network: [HTTP, GRPC]
+loggers:
+  - category: YAML
+    level: warning
+    max_message_size: 64
+    tags: [noisy]
+    outputs:
+      - path: discard
+        format: json
+        level: error
+`
+
+const tomlConfig = `
+padding = false
+category_width = 12
+
+[groups]
+network = ["HTTP", "GRPC"]
+
+[[loggers]]
+category = "TOML"
+level = "warning"
+max_message_size = 64
+tags = ["noisy"]
+
+  [[loggers.outputs]]
+  path = "discard"
+  format = "json"
+  level = "error"
+`
+
+func TestUnmarshal(t *testing.T) {
+	for name, test := range map[string]struct {
+		unmarshal func([]byte, interface{}) error
+		data      string
+		category  string
+	}{
+		"yaml": {UnmarshalYAML, yamlConfig, "YAML"},
+		"toml": {UnmarshalTOML, tomlConfig, "TOML"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var cfg logger.Config
+			if err := test.unmarshal([]byte(test.data), &cfg); err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Padding == nil || *cfg.Padding || cfg.CategoryWidth == nil || *cfg.CategoryWidth != 12 {
+				t.Errorf("package options were not decoded: %+v", cfg)
+			}
+			if groups := cfg.Groups["network"]; len(groups) != 2 || groups[1] != "GRPC" {
+				t.Errorf("groups were not decoded: %v", cfg.Groups)
+			}
+			if len(cfg.Loggers) != 1 {
+				t.Fatalf("expected 1 logger, got %d", len(cfg.Loggers))
+			}
+			lc := cfg.Loggers[0]
+			if lc.Category != test.category || lc.Level != "warning" || lc.MaxMessageSize != 64 ||
+				len(lc.Tags) != 1 || lc.Tags[0] != "noisy" {
+				t.Errorf("logger was not decoded: %+v", lc)
+			}
+			if len(lc.Outputs) != 1 || lc.Outputs[0] != (logger.OutputConfig{Path: "discard", Format: "json", Level: "error"}) {
+				t.Errorf("outputs were not decoded: %+v", lc.Outputs)
+			}
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []struct{ name, data, category string }{
+		{"logging.yaml", yamlConfig, "YAML"},
+		{"logging.yml", yamlConfig, "YAML"},
+		{"logging.toml", tomlConfig, "TOML"},
+	} {
+		path := filepath.Join(dir, file.name)
+		if err := ioutil.WriteFile(path, []byte(file.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		loggers, err := logger.Configure(path)
+		if err != nil {
+			t.Fatalf("%s: %s", file.name, err)
+		}
+		l := loggers[file.category]
+		if l == nil || l.CurrentLevel() != logger.LevelWarning || !l.HasTag("noisy") {
+			t.Errorf("%s: logger was not configured: %+v", file.name, l)
+		}
+		logger.RemoveLogger(l)
+	}
+}
+
+func TestUnmarshalYAMLRejectsNonStringKeys(t *testing.T) {
+	var cfg logger.Config
+	if err := UnmarshalYAML([]byte("groups:\n  1: [HTTP]\n"), &cfg); err == nil {
+		t.Error("expected an error for a non-string key")
+	}
+}
@@ -0,0 +1,76 @@
+package logger
+
+import "context"
+
+// Field keys used to correlate entries with the trace and span that were active when they were logged.
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// TraceExtractor returns the hex encoded trace and span IDs of the active span carried by a context. ok is false if
+// the context does not carry a valid span.
+type TraceExtractor func(ctx context.Context) (traceID, spanID string, ok bool)
+
+var traceExtractor TraceExtractor
+
+// SetTraceExtractor sets the function used by the LogxContext functions to find the active span of a context. Entries
+// logged with a context carrying an active span then have trace_id and span_id Fields attached. Importing the oteltrace
+// subpackage sets an extractor for OpenTelemetry spans. Passing nil disables trace correlation.
+func SetTraceExtractor(extractor TraceExtractor) {
+	traceExtractor = extractor
+}
+
+// contextFields returns the Fields to attach to entries logged with the provided context: the trace and span IDs of
+// its active span, followed by any Fields added with WithFields.
+func contextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+
+	var fields []Field
+	if traceExtractor != nil {
+		if traceID, spanID, ok := traceExtractor(ctx); ok {
+			fields = append(fields, String(TraceIDKey, traceID), String(SpanIDKey, spanID))
+		}
+	}
+	return append(fields, FieldsFromContext(ctx)...)
+}
+
+// LogContext logs the provided message if the Logger is enabled, attaching the trace and span of ctx.
+func (l *Logger) LogContext(ctx context.Context, msg ...interface{}) {
+	l.performLazyLog(lazySprint(msg), false, contextFields(ctx))
+}
+
+// LogfContext logs the provided message with formatting if the Logger is enabled, attaching the trace and span of ctx.
+func (l *Logger) LogfContext(ctx context.Context, format string, args ...interface{}) {
+	l.performLazyLog(lazySprintf(format, args), false, contextFields(ctx))
+}
+
+// LoglnContext logs the provided message followed by a new line if the Logger is enabled, attaching the trace and span of
+// ctx.
+func (l *Logger) LoglnContext(ctx context.Context, msg ...interface{}) {
+	l.performLazyLog(lazySprint(msg), true, contextFields(ctx))
+}
+
+// LogFieldsContext logs the provided message with fields attached if the Logger is enabled, also attaching the trace and
+// span of ctx.
+func (l *Logger) LogFieldsContext(ctx context.Context, message string, fields ...Field) {
+	l.performLog(message, false, append(contextFields(ctx), fields...))
+}
+
+// LogContext logs the provided message if the Logger is enabled, attaching the trace and span of ctx.
+func LogContext(ctx context.Context, logger *Logger, msg ...interface{}) {
+	logger.performLazyLog(lazySprint(msg), false, contextFields(ctx))
+}
+
+// LogfContext logs the provided message with formatting if the Logger is enabled, attaching the trace and span of ctx.
+func LogfContext(ctx context.Context, logger *Logger, format string, args ...interface{}) {
+	logger.performLazyLog(lazySprintf(format, args), false, contextFields(ctx))
+}
+
+// LoglnContext logs the provided message followed by a new line if the Logger is enabled, attaching the trace and span of
+// ctx.
+func LoglnContext(ctx context.Context, logger *Logger, msg ...interface{}) {
+	logger.performLazyLog(lazySprint(msg), true, contextFields(ctx))
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// counters are the named counters incremented by Loggers with SetCounter, keyed by name.
+var counters = struct {
+	mu     sync.RWMutex
+	values map[string]*int64
+	// stop stops the periodic counter summary, if one is running.
+	stop func()
+}{
+	values: make(map[string]*int64),
+}
+
+// SetCounter makes every message logged by the Logger increment the named counter. Counters can be shared by several
+// Loggers, i.e. SetCounter("errors") on both an ERROR and a FATAL logger. Messages are counted once they are queued to be
+// written, so messages logged while the Logger is disabled or dropped by sampling, rate limiting or filters are not
+// counted. An empty name stops the Logger incrementing a counter.
+func (l *Logger) SetCounter(name string) {
+	l.counterName = name
+	l.counterEnabled = name != ""
+	if l.counterEnabled {
+		// register the counter, so that it is reported before anything has been counted
+		counterValue(name)
+	}
+}
+
+// counterValue returns the named counter, creating it if necessary.
+func counterValue(name string) *int64 {
+	counters.mu.RLock()
+	value, ok := counters.values[name]
+	counters.mu.RUnlock()
+	if ok {
+		return value
+	}
+
+	counters.mu.Lock()
+	defer counters.mu.Unlock()
+	if value, ok = counters.values[name]; !ok {
+		value = new(int64)
+		counters.values[name] = value
+	}
+	return value
+}
+
+// incrementCounter increments the Logger's named counter, if it has one.
+func (l *Logger) incrementCounter() {
+	if l.counterEnabled {
+		atomic.AddInt64(counterValue(l.counterName), 1)
+	}
+}
+
+// Counters returns a snapshot of every named counter, keyed by name.
+func Counters() map[string]int64 {
+	counters.mu.RLock()
+	defer counters.mu.RUnlock()
+	snapshot := make(map[string]int64, len(counters.values))
+	for name, value := range counters.values {
+		snapshot[name] = atomic.LoadInt64(value)
+	}
+	return snapshot
+}
+
+// ResetCounters sets the named counters to zero, or every counter if no names are provided.
+func ResetCounters(names ...string) {
+	counters.mu.RLock()
+	defer counters.mu.RUnlock()
+	if len(names) == 0 {
+		for _, value := range counters.values {
+			atomic.StoreInt64(value, 0)
+		}
+		return
+	}
+	for _, name := range names {
+		if value, ok := counters.values[name]; ok {
+			atomic.StoreInt64(value, 0)
+		}
+	}
+}
+
+// StartCounterSummary logs a summary of every named counter with the Internal logger every interval, i.e.
+// "counters errors=3 requests=1200", with each counter as a field in name order. If reset is set, the counters are
+// reset after each summary, so that each summary reports the counts since the previous one. Any previous summary is
+// stopped.
+func StartCounterSummary(interval time.Duration, reset bool) {
+	StopCounterSummary()
+	stop := every(interval, func() {
+		logCounterSummary(reset)
+	})
+	counters.mu.Lock()
+	counters.stop = stop
+	counters.mu.Unlock()
+}
+
+// StopCounterSummary stops logging the periodic counter summary.
+func StopCounterSummary() {
+	counters.mu.Lock()
+	if counters.stop != nil {
+		counters.stop()
+		counters.stop = nil
+	}
+	counters.mu.Unlock()
+}
+
+// logCounterSummary logs the value of every named counter as a field, optionally resetting each one as it is read.
+func logCounterSummary(reset bool) {
+	counters.mu.RLock()
+	names := make([]string, 0, len(counters.values))
+	for name := range counters.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]Field, len(names))
+	for i, name := range names {
+		var value int64
+		if reset {
+			value = atomic.SwapInt64(counters.values[name], 0)
+		} else {
+			value = atomic.LoadInt64(counters.values[name])
+		}
+		fields[i] = Int64(name, value)
+	}
+	counters.mu.RUnlock()
+
+	if len(fields) > 0 {
+		Internal.LogFields("counters", fields...)
+	}
+}
+
+// clearCounters stops the counter summary and removes every named counter.
+func clearCounters() {
+	StopCounterSummary()
+	counters.mu.Lock()
+	counters.values = make(map[string]*int64)
+	counters.mu.Unlock()
+}
@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DatadogIntakeURL is the Datadog HTTP logs intake endpoint (US1 site) which DatadogSinks send batches to by default.
+const DatadogIntakeURL = "https://http-intake.logs.datadoghq.com/api/v2/logs"
+
+// DatadogSink is a Sink which sends entries to the Datadog HTTP logs intake. Entries are queued into batches which are
+// gzip compressed and sent when BatchSize entries have been queued or every FlushInterval, whichever happens first.
+// Batches which are rate limited (429 Too Many Requests) are retried up to MaxRetries times, honouring Retry-After.
+//
+// Each entry's service and source are tagged with its lower case Category Name unless Service or Source are set. The
+// zero value sends entries to DatadogIntakeURL without authentication, so APIKey must be set before use.
+type DatadogSink struct {
+	APIKey   string
+	URL      string
+	Service  string
+	Source   string
+	Hostname string
+	// Tags are additional comma separated key:value tags attached to every entry, i.e. "env:prod,team:payments".
+	Tags string
+
+	BatchSize     int
+	FlushInterval time.Duration
+	MaxRetries    int
+	Client        *http.Client
+
+	batcherOnce sync.Once
+	batcher     *batcher
+}
+
+// datadogLog is a single log in the intake request body.
+type datadogLog struct {
+	Message  string `json:"message"`
+	Status   string `json:"status"`
+	Service  string `json:"service,omitempty"`
+	Source   string `json:"ddsource,omitempty"`
+	Tags     string `json:"ddtags,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+	Date     int64  `json:"date"`
+}
+
+// NewDatadogSink creates a DatadogSink which authenticates with the provided API key. The background batch sender is
+// started by the first entry written, so the sink's fields can be modified before use. Close must be called to flush
+// any remaining entries before the program terminates.
+func NewDatadogSink(apiKey string) *DatadogSink {
+	hostname, _ := os.Hostname()
+	s := &DatadogSink{
+		APIKey:        apiKey,
+		URL:           DatadogIntakeURL,
+		Hostname:      hostname,
+		BatchSize:     500,
+		FlushInterval: 5 * time.Second,
+		MaxRetries:    3,
+		Client:        &http.Client{Timeout: 30 * time.Second},
+	}
+	s.batcher = newBatcher(s.send)
+	return s
+}
+
+// queue returns the sink's batcher, creating it if the sink was not created with NewDatadogSink.
+func (s *DatadogSink) queue() *batcher {
+	s.batcherOnce.Do(func() {
+		if s.batcher == nil {
+			s.batcher = newBatcher(s.send)
+		}
+	})
+	return s.batcher
+}
+
+// WriteEntry queues the entry to be sent with the next batch. If a batch has failed to be sent since the previous
+// entry was written, its error is returned and the entry is not queued.
+func (s *DatadogSink) WriteEntry(e Entry) error {
+	return s.queue().add(e, s.BatchSize, s.FlushInterval)
+}
+
+// datadogLog converts an entry into a log for the intake request body.
+func (s *DatadogSink) datadogLog(e Entry) datadogLog {
+	category := strings.ToLower(e.Category)
+	log := datadogLog{
+		Message:  e.Message,
+		Status:   datadogStatus(e.Level),
+		Service:  s.Service,
+		Source:   s.Source,
+		Tags:     s.Tags,
+		Hostname: s.Hostname,
+		Date:     e.Time.UnixNano() / int64(time.Millisecond),
+	}
+	if log.Service == "" {
+		log.Service = category
+	}
+	if log.Source == "" {
+		log.Source = category
+	}
+	if e.Category != "" {
+		if log.Tags != "" {
+			log.Tags += ","
+		}
+		log.Tags += "category:" + category
+	}
+	return log
+}
+
+// Write implements io.Writer so that a DatadogSink can be used as a Logger's Writer. Raw writes are sent as LevelInfo
+// entries without a category.
+func (s *DatadogSink) Write(p []byte) (int, error) {
+	message := strings.TrimSpace(string(p))
+	if message == "" {
+		return len(p), nil
+	}
+	if err := s.WriteEntry(Entry{Time: now(), Level: LevelInfo, Message: message}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush sends any queued entries to the intake immediately.
+func (s *DatadogSink) Flush() error {
+	return s.queue().flush()
+}
+
+// Close stops the background batch sender once any queued entries have been sent, returning the error of the final
+// batch.
+func (s *DatadogSink) Close() error {
+	return s.queue().close()
+}
+
+// send gzip compresses and posts a batch, retrying when the intake responds with 429 Too Many Requests.
+func (s *DatadogSink) send(batch []Entry) error {
+	logs := make([]datadogLog, 0, len(batch))
+	for _, e := range batch {
+		logs = append(logs, s.datadogLog(e))
+	}
+
+	var body bytes.Buffer
+	gz := gzip.NewWriter(&body)
+	if err := json.NewEncoder(gz).Encode(logs); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	url := s.URL
+	if url == "" {
+		url = DatadogIntakeURL
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Encoding", "gzip")
+		req.Header.Set("DD-API-KEY", s.APIKey)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= s.MaxRetries {
+			if resp.StatusCode >= 300 {
+				return fmt.Errorf("datadog: unexpected response status: %s", resp.Status)
+			}
+			return nil
+		}
+
+		// wait before retrying a rate limited batch
+		wait := backoff
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			wait = time.Duration(seconds) * time.Second
+		}
+		time.Sleep(wait)
+		backoff *= 2
+	}
+}
+
+// datadogStatus maps a Level onto a Datadog log status.
+func datadogStatus(lvl Level) string {
+	switch {
+	case lvl >= LevelFatal:
+		return "critical"
+	case lvl >= LevelError:
+		return "error"
+	case lvl >= LevelWarning:
+		return "warn"
+	case lvl >= LevelInfo:
+		return "info"
+	}
+	return "debug"
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	defaultLoggers   = make(map[Level]*Logger)
+	defaultLoggersMu sync.Mutex
+)
+
+// DefaultLogger returns the Logger used by the package-level functions of the provided Level, i.e. Info and Infof. The
+// default loggers are only created when first used, so that they do not affect the IDs of other loggers unless they are
+// needed: DEBUG (disabled) and INFO write to Stdout, while WARNING, ERROR and FATAL write to Stderr. Any other Level
+// returns the INFO Logger.
+func DefaultLogger(lvl Level) *Logger {
+	defaultLoggersMu.Lock()
+	defer defaultLoggersMu.Unlock()
+
+	if l, ok := defaultLoggers[lvl]; ok {
+		return l
+	}
+
+	var (
+		w       io.Writer = os.Stdout
+		enabled           = true
+	)
+	switch lvl {
+	case LevelDebug:
+		enabled = false
+	case LevelWarning, LevelError, LevelFatal:
+		w = os.Stderr
+	case LevelInfo:
+	default:
+		lvl = LevelInfo
+		if l, ok := defaultLoggers[lvl]; ok {
+			return l
+		}
+	}
+	l := NewLogger(w, lvl.String(), enabled)
+	defaultLoggers[lvl] = l
+	return l
+}
+
+// SetDefaultLogger replaces the Logger used by the package-level functions of the provided Level, i.e.
+// SetDefaultLogger(LevelError, myErrorLogger) directs Error and Errorf to myErrorLogger.
+func SetDefaultLogger(lvl Level, l *Logger) {
+	defaultLoggersMu.Lock()
+	defaultLoggers[lvl] = l
+	defaultLoggersMu.Unlock()
+}
+
+// Debug logs the provided message with the default DEBUG Logger, which is disabled unless enabled via DefaultLogger.
+func Debug(msg ...interface{}) {
+	DefaultLogger(LevelDebug).performLazyLog(lazySprint(msg), false, nil)
+}
+
+// Debugf logs the provided message with formatting with the default DEBUG Logger.
+func Debugf(format string, args ...interface{}) {
+	DefaultLogger(LevelDebug).performLazyLog(lazySprintf(format, args), false, nil)
+}
+
+// Info logs the provided message with the default INFO Logger.
+func Info(msg ...interface{}) {
+	DefaultLogger(LevelInfo).performLazyLog(lazySprint(msg), false, nil)
+}
+
+// Infof logs the provided message with formatting with the default INFO Logger.
+func Infof(format string, args ...interface{}) {
+	DefaultLogger(LevelInfo).performLazyLog(lazySprintf(format, args), false, nil)
+}
+
+// Warning logs the provided message with the default WARNING Logger.
+func Warning(msg ...interface{}) {
+	DefaultLogger(LevelWarning).performLazyLog(lazySprint(msg), false, nil)
+}
+
+// Warningf logs the provided message with formatting with the default WARNING Logger.
+func Warningf(format string, args ...interface{}) {
+	DefaultLogger(LevelWarning).performLazyLog(lazySprintf(format, args), false, nil)
+}
+
+// Error logs the provided message with the default ERROR Logger.
+func Error(msg ...interface{}) {
+	DefaultLogger(LevelError).performLazyLog(lazySprint(msg), false, nil)
+}
+
+// Errorf logs the provided message with formatting with the default ERROR Logger.
+func Errorf(format string, args ...interface{}) {
+	DefaultLogger(LevelError).performLazyLog(lazySprintf(format, args), false, nil)
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// DeterministicTime is the timestamp given to every message while deterministic mode is enabled.
+	DeterministicTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	deterministic   bool
+	deterministicMu sync.Mutex
+	// deterministicPrevious is the grouping state of synchronous writes, which do not group categories.
+	deterministicPrevious string
+)
+
+// SetDeterministic enables or disables deterministic mode, in which output is byte-for-byte reproducible for golden-file
+// tests of formatters and encoders. While enabled, every message is timestamped with DeterministicTime, category
+// grouping does not carry over from one message to the next, and messages are written synchronously by the caller of
+// the Logx function rather than by the poller.
+func SetDeterministic(enabled bool) {
+	deterministicMu.Lock()
+	deterministic = enabled
+	deterministicMu.Unlock()
+}
+
+// writeSynchronously writes a message from the caller's goroutine, serialising writes between callers.
+func writeSynchronously(item queueItem) {
+	deterministicMu.Lock()
+	defer deterministicMu.Unlock()
+	performWrite(item, &deterministicPrevious)
+}
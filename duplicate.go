@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// duplicateSuppressor collapses identical consecutive messages from a Logger into a single "last message repeated N
+// times" message, in the same way as syslog.
+type duplicateSuppressor struct {
+	mu       sync.Mutex
+	window   time.Duration
+	previous string
+	repeats  int
+	// stopTimer stops the scheduled summary of the repeats.
+	stopTimer func() bool
+}
+
+// SetDuplicateSuppression enables the suppression of identical consecutive messages. Repeats of the previous message
+// are dropped and a "last message repeated N times" message is written once a different message is logged or window
+// has elapsed since the first repeat, whichever happens first. A window of 0 or less disables suppression.
+func (l *Logger) SetDuplicateSuppression(window time.Duration) {
+	if window <= 0 {
+		l.duplicates = nil
+		return
+	}
+	l.duplicates = &duplicateSuppressor{window: window}
+}
+
+// suppress reports whether the message is a repeat of the previous message and should be dropped. If the message
+// differs from the previous one, any pending repeat summary is written first.
+func (d *duplicateSuppressor) suppress(l *Logger, message string, fields []Field) bool {
+	key := message
+	if len(fields) > 0 {
+		key += "\x00" + composeFields(fields)
+	}
+
+	d.mu.Lock()
+	if key == d.previous {
+		d.repeats++
+		if d.stopTimer == nil {
+			d.stopTimer = afterFunc(d.window, func() {
+				d.mu.Lock()
+				repeats := d.reset()
+				d.mu.Unlock()
+				summariseRepeats(l, repeats)
+			})
+		}
+		d.mu.Unlock()
+		return true
+	}
+
+	repeats := d.reset()
+	d.previous = key
+	d.mu.Unlock()
+	summariseRepeats(l, repeats)
+	return false
+}
+
+// reset stops any pending summary and returns the number of repeats which have not yet been summarised. d.mu must be
+// held.
+func (d *duplicateSuppressor) reset() int {
+	if d.stopTimer != nil {
+		d.stopTimer()
+		d.stopTimer = nil
+	}
+	repeats := d.repeats
+	d.repeats = 0
+	return repeats
+}
+
+// summariseRepeats writes the number of times the previous message was repeated, if it was repeated.
+func summariseRepeats(l *Logger, repeats int) {
+	if repeats == 0 || !l.IsEnabled() {
+		return
+	}
+	if repeats == 1 {
+		l.enqueue("last message repeated 1 time", false, nil)
+		return
+	}
+	l.enqueue("last message repeated "+strconv.Itoa(repeats)+" times", false, nil)
+}
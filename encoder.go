@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EncoderFunc converts an Entry into the bytes written to a Writer, including any trailing newline. Encoders allow each
+// Writer of a MultiSink to receive entries in its own format, i.e. coloured text for a console and JSON for a file.
+type EncoderFunc func(e Entry) []byte
+
+// encoderTimeFormat is the timestamp layout used by TextEncoder and ColorEncoder, matching the default Timestamp Format.
+const encoderTimeFormat = "01/02 15:04:05"
+
+// levelColors are the ANSI escape codes used by ColorEncoder for each Level.
+var levelColors = map[Level]string{
+	LevelDebug:   "\x1b[90m",
+	LevelInfo:    "\x1b[36m",
+	LevelWarning: "\x1b[33m",
+	LevelError:   "\x1b[31m",
+	LevelFatal:   "\x1b[35m",
+}
+
+// colorReset is the ANSI escape code which ends a colour started by Level.Color.
+const colorReset = "\x1b[0m"
+
+// Color returns the ANSI escape code used by ColorEncoder to colour the Level, i.e. for tools which render logs in a
+// terminal. Text coloured with it should be followed by "\x1b[0m" to reset the colour.
+func (lvl Level) Color() string {
+	return levelColors[lvl]
+}
+
+var (
+	// TextEncoder encodes entries as plain text lines in the form "[CATEGORY] 01/02 15:04:05 message key=value".
+	TextEncoder EncoderFunc = func(e Entry) []byte {
+		return encodeText(e, "", "")
+	}
+	// ColorEncoder encodes entries in the same form as TextEncoder, colouring the category by Level with ANSI escape
+	// codes for display in a terminal.
+	ColorEncoder EncoderFunc = func(e Entry) []byte {
+		return encodeText(e, e.Level.Color(), colorReset)
+	}
+	// JSONEncoder encodes entries as single line JSON objects containing the sequence number, time, level, category and
+	// message, followed by each field as a top level key.
+	JSONEncoder EncoderFunc = func(e Entry) []byte {
+		var b bytes.Buffer
+		b.WriteString(`{"seq":`)
+		writeJSON(&b, e.Sequence)
+		b.WriteString(`,"time":`)
+		writeJSON(&b, e.Time.Format(time.RFC3339Nano))
+		b.WriteString(`,"level":`)
+		writeJSON(&b, e.Level.String())
+		if e.Category != "" {
+			b.WriteString(`,"category":`)
+			writeJSON(&b, e.Category)
+		}
+		b.WriteString(`,"message":`)
+		writeJSON(&b, e.Message)
+		for _, f := range e.Fields {
+			b.WriteByte(',')
+			writeJSON(&b, f.Key)
+			b.WriteByte(':')
+			writeJSONField(&b, f)
+		}
+		b.WriteString("}\n")
+		return b.Bytes()
+	}
+)
+
+// ParseEncoder returns the encoder named by format, ignoring case: "color" for ColorEncoder and "json" for JSONEncoder.
+// "text" and the empty string return a nil encoder, which writes entries in the Logger's own format.
+func ParseEncoder(format string) (EncoderFunc, bool) {
+	switch strings.ToLower(format) {
+	case "", "text":
+		return nil, true
+	case "color":
+		return ColorEncoder, true
+	case "json":
+		return JSONEncoder, true
+	}
+	return nil, false
+}
+
+// encodeText formats an entry as a line of text, wrapping the category in the provided escape codes.
+func encodeText(e Entry, colorStart, colorEnd string) []byte {
+	var b bytes.Buffer
+	if e.Category != "" {
+		b.WriteString(colorStart + "[" + e.Category + "]" + colorEnd + " ")
+	}
+	b.WriteString(e.Time.Format(encoderTimeFormat))
+	b.WriteByte(' ')
+	b.WriteString(e.Message)
+	if len(e.Fields) > 0 {
+		b.WriteByte(' ')
+		writeFields(&b, e.Fields)
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+// writeJSON writes v to b as JSON. Values which cannot be marshalled are written as their text representation.
+func writeJSON(b *bytes.Buffer, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(Any("", v).String())
+	}
+	b.Write(data)
+}
+
+// writeJSONField writes the value of a Field to b as JSON, appending integers and bools directly rather than boxing them
+// for json.Marshal.
+func writeJSONField(b *bytes.Buffer, f Field) {
+	switch f.kind {
+	case fieldInt, fieldUint, fieldBool:
+		var scratch [32]byte
+		b.Write(f.appendValue(scratch[:0]))
+	case fieldDuration:
+		var scratch [32]byte
+		b.Write(strconv.AppendInt(scratch[:0], int64(f.num), 10))
+	default:
+		writeJSON(b, f.Value())
+	}
+}
@@ -0,0 +1,289 @@
+package logger
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// encryptedFileMagic is written at the start of every encrypted log file to identify its format. Files written before
+// chunks were bound to their position start with encryptedFileMagicV1, and contain chunks without sessions.
+const (
+	encryptedFileMagic   = "LOGENC2\n"
+	encryptedFileMagicV1 = "LOGENC1\n"
+)
+
+// maxEncryptedChunk is the largest chunk DecryptLog will accept, protecting it from corrupt length prefixes.
+const maxEncryptedChunk = 64 * 1024 * 1024
+
+// encryptedSessionSize is the length of the random ID which starts each session of an encrypted log.
+const encryptedSessionSize = 16
+
+// EncryptedFile is a Writer which encrypts logs at rest using AES-GCM. Each time the file is opened a session is
+// started, marked by a zero length followed by a random session ID. Each write (a single composed message) is then
+// sealed as its own chunk with a random nonce and appended to the file as a 4 byte big-endian length followed by the
+// nonce and ciphertext. Closing or reopening the file ends the session with an empty final chunk. Every chunk is
+// authenticated together with its session ID, its position in the session and whether it is the final chunk, so
+// DecryptLog rejects chunks which have been reordered, removed, duplicated or moved between sessions, and sessions
+// which have been truncated. Sessions themselves are not ordered, so whole sessions removed from a file, or a file
+// replaced by an older copy of itself, are not detected.
+//
+// Nonces are random, so a key must not be used to encrypt more than 2^32 (about 4 billion) messages across all of
+// its files, beyond which a nonce is likely enough to be repeated that the key should be considered exposed. Keys
+// should be rotated well before then.
+//
+// Files written by an EncryptedFile can be read with DecryptLog, or with the "decrypt" command of cmd/logger.
+type EncryptedFile struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	aead    cipher.AEAD
+	session []byte
+	counter uint64
+}
+
+// OpenEncryptedFile opens (or creates) the file at path for appending encrypted logs. The key must be 16, 24 or 32 bytes
+// long to select AES-128, AES-192 or AES-256.
+func OpenEncryptedFile(path string, key []byte) (*EncryptedFile, error) {
+	aead, err := newLogAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	file, session, err := openEncryptedFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedFile{path: path, file: file, aead: aead, session: session}, nil
+}
+
+// openEncryptedFile opens path for appending, writing the format header if the file is new, and starts a new session.
+// Existing files must have been written in the current format, as chunks of different versions cannot be mixed in one
+// file.
+func openEncryptedFile(path string) (*os.File, []byte, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	if info.Size() == 0 {
+		if _, err := io.WriteString(file, encryptedFileMagic); err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+	} else {
+		magic := make([]byte, len(encryptedFileMagic))
+		if _, err := file.ReadAt(magic, 0); err != nil || string(magic) != encryptedFileMagic {
+			file.Close()
+			return nil, nil, fmt.Errorf("%s is not an encrypted log of the current version", path)
+		}
+	}
+
+	session, err := startEncryptedSession(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return file, session, nil
+}
+
+// startEncryptedSession appends the start of a new session to file, returning its random ID.
+func startEncryptedSession(file *os.File) ([]byte, error) {
+	session := make([]byte, encryptedSessionSize)
+	if _, err := rand.Read(session); err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(append([]byte{0, 0, 0, 0}, session...)); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Write encrypts p as a single chunk and appends it to the file.
+func (f *EncryptedFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.writeChunk(p, false); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeChunk seals p as the next chunk of the session and appends it to the file. The mutex must be held.
+func (f *EncryptedFile) writeChunk(p []byte, final bool) error {
+	nonce := make([]byte, f.aead.NonceSize(), f.aead.NonceSize()+len(p)+f.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	chunk := f.aead.Seal(nonce, nonce, p, encryptedChunkAD(f.session, f.counter, final))
+
+	buf := make([]byte, 4+len(chunk))
+	binary.BigEndian.PutUint32(buf, uint32(len(chunk)))
+	copy(buf[4:], chunk)
+	if _, err := f.file.Write(buf); err != nil {
+		return err
+	}
+	f.counter++
+	return nil
+}
+
+// Reopen ends the session and closes the file, then opens its path again and starts a new session, so that writing
+// continues to a new file once the old one has been moved aside by log rotation.
+func (f *EncryptedFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// the session is ended first, as the file may not have been moved aside
+	if err := f.writeChunk(nil, true); err != nil {
+		return err
+	}
+	file, session, err := openEncryptedFile(f.path)
+	if err != nil {
+		// continue writing to the current file in a new session
+		if session, startErr := startEncryptedSession(f.file); startErr == nil {
+			f.session, f.counter = session, 0
+		}
+		return err
+	}
+
+	old := f.file
+	f.file, f.session, f.counter = file, session, 0
+	return old.Close()
+}
+
+// Close ends the session with its final chunk and closes the underlying file.
+func (f *EncryptedFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	finalErr := f.writeChunk(nil, true)
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	return finalErr
+}
+
+// encryptedChunkAD returns the additional data which a chunk is authenticated with: the format, the ID of its session,
+// its position in the session and whether it is the final chunk of the session.
+func encryptedChunkAD(session []byte, counter uint64, final bool) []byte {
+	ad := make([]byte, 0, len(encryptedFileMagic)+len(session)+9)
+	ad = append(ad, encryptedFileMagic...)
+	ad = append(ad, session...)
+	ad = binary.BigEndian.AppendUint64(ad, counter)
+	if final {
+		return append(ad, 1)
+	}
+	return append(ad, 0)
+}
+
+// DecryptLog reads an encrypted log written by an EncryptedFile from r and writes the decrypted messages to w. An error
+// is returned for the first chunk which fails to decrypt, including chunks which are out of place, and messages before
+// it are still written. Sessions which end without their final chunk, i.e. because the file was truncated, the program
+// writing it crashed or it is still being written, are reported once the rest of the log has been decrypted. Files
+// written by earlier versions of EncryptedFile are also accepted, but their chunks are not protected against being
+// reordered, removed or truncated.
+func DecryptLog(w io.Writer, r io.Reader, key []byte) error {
+	aead, err := newLogAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(encryptedFileMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return errors.New("not an encrypted log file")
+	}
+	legacy := string(magic) == encryptedFileMagicV1
+	if string(magic) != encryptedFileMagic && !legacy {
+		return errors.New("not an encrypted log file")
+	}
+
+	var (
+		size      [4]byte
+		session   []byte
+		counter   uint64
+		ended     = true
+		truncated error
+	)
+	for chunkNo := 1; ; chunkNo++ {
+		if _, err := io.ReadFull(br, size[:]); err != nil {
+			if err == io.EOF {
+				if !ended && truncated == nil {
+					truncated = fmt.Errorf("log is truncated after chunk %d, as its session has no final chunk", chunkNo-1)
+				}
+				return truncated
+			}
+			return fmt.Errorf("failed to read chunk %d: %s", chunkNo, err)
+		}
+		n := binary.BigEndian.Uint32(size[:])
+
+		// a zero length starts a new session
+		if n == 0 && !legacy {
+			if !ended && truncated == nil {
+				truncated = fmt.Errorf("session is truncated before chunk %d, as it has no final chunk", chunkNo)
+			}
+			session = make([]byte, encryptedSessionSize)
+			if _, err := io.ReadFull(br, session); err != nil {
+				return fmt.Errorf("failed to read the session of chunk %d: %s", chunkNo, err)
+			}
+			counter, ended = 0, false
+			chunkNo--
+			continue
+		}
+		if n < uint32(aead.NonceSize()) || n > maxEncryptedChunk {
+			return fmt.Errorf("chunk %d has an invalid length of %d", chunkNo, n)
+		}
+
+		chunk := make([]byte, n)
+		if _, err := io.ReadFull(br, chunk); err != nil {
+			return fmt.Errorf("failed to read chunk %d: %s", chunkNo, err)
+		}
+		nonce, ciphertext := chunk[:aead.NonceSize()], chunk[aead.NonceSize():]
+		if legacy {
+			plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt chunk %d: %s", chunkNo, err)
+			}
+			if _, err := w.Write(plaintext); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if ended {
+			return fmt.Errorf("chunk %d is outside of a session", chunkNo)
+		}
+		plaintext, err := aead.Open(nil, nonce, ciphertext, encryptedChunkAD(session, counter, false))
+		if err != nil {
+			// the final chunk of a session is empty, and is authenticated as the final chunk
+			if _, finalErr := aead.Open(nil, nonce, ciphertext, encryptedChunkAD(session, counter, true)); finalErr != nil {
+				return fmt.Errorf("failed to decrypt chunk %d: %s", chunkNo, err)
+			}
+			ended = true
+			continue
+		}
+		counter++
+		if _, err := w.Write(plaintext); err != nil {
+			return err
+		}
+	}
+}
+
+// newLogAEAD creates the AES-GCM cipher used to encrypt and decrypt logs.
+func newLogAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+// encryptedRecords splits an encrypted log into its header and the raw bytes of each session marker and chunk.
+func encryptedRecords(t *testing.T, data []byte) (header []byte, records [][]byte) {
+	t.Helper()
+	header, data = data[:len(encryptedFileMagic)], data[len(encryptedFileMagic):]
+	for len(data) > 0 {
+		n := int(binary.BigEndian.Uint32(data))
+		if n == 0 {
+			n = encryptedSessionSize
+		}
+		records = append(records, data[:4+n])
+		data = data[4+n:]
+	}
+	return header, records
+}
+
+// writeEncryptedLog writes two sessions of messages to an encrypted log and returns its contents.
+func writeEncryptedLog(t *testing.T) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.log.enc")
+	f, err := OpenEncryptedFile(path, testEncryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range []string{"one\n", "two\n", "three\n"} {
+		if _, err := f.Write([]byte(message)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("four\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestEncryptedFileRoundTrip(t *testing.T) {
+	data := writeEncryptedLog(t)
+
+	var out bytes.Buffer
+	if err := DecryptLog(&out, bytes.NewReader(data), testEncryptionKey); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "one\ntwo\nthree\nfour\n" {
+		t.Errorf("unexpected decrypted log %q", out.String())
+	}
+
+	wrongKey := []byte("fedcba9876543210fedcba9876543210")
+	if err := DecryptLog(ioutil.Discard, bytes.NewReader(data), wrongKey); err == nil {
+		t.Error("expected decrypting with the wrong key to fail")
+	}
+}
+
+func TestDecryptLogDetectsTampering(t *testing.T) {
+	data := writeEncryptedLog(t)
+	header, records := encryptedRecords(t, data)
+	// records: session, one, two, three, final, session, four, final
+	if len(records) != 8 {
+		t.Fatalf("expected 8 records, got %d", len(records))
+	}
+
+	tests := map[string][][]byte{
+		"reordered":       {records[0], records[2], records[1], records[3], records[4], records[5], records[6], records[7]},
+		"removed":         {records[0], records[1], records[3], records[4], records[5], records[6], records[7]},
+		"duplicated":      {records[0], records[1], records[1], records[2], records[3], records[4]},
+		"moved session":   {records[0], records[1], records[6], records[2], records[3], records[4]},
+		"truncated":       {records[0], records[1], records[2], records[3], records[4], records[5], records[6]},
+		"truncated early": {records[0], records[1], records[2], records[5], records[6], records[7]},
+		"after final":     {records[0], records[1], records[4], records[2]},
+	}
+	for name, tampered := range tests {
+		t.Run(name, func(t *testing.T) {
+			log := append([]byte(nil), header...)
+			for _, record := range tampered {
+				log = append(log, record...)
+			}
+			if err := DecryptLog(ioutil.Discard, bytes.NewReader(log), testEncryptionKey); err == nil {
+				t.Error("expected the tampered log to be rejected")
+			}
+		})
+	}
+}
+
+func TestDecryptLogContinuesAfterTruncatedSession(t *testing.T) {
+	data := writeEncryptedLog(t)
+	header, records := encryptedRecords(t, data)
+
+	// a session without its final chunk, i.e. after a crash, is reported once the rest of the log has been written
+	log := append([]byte(nil), header...)
+	for _, record := range append(records[:4:4], records[5:]...) {
+		log = append(log, record...)
+	}
+	var out bytes.Buffer
+	err := DecryptLog(&out, bytes.NewReader(log), testEncryptionKey)
+	if err == nil || !strings.Contains(err.Error(), "truncated") {
+		t.Errorf("expected the truncated session to be reported, got %v", err)
+	}
+	if out.String() != "one\ntwo\nthree\nfour\n" {
+		t.Errorf("expected every message to be decrypted, got %q", out.String())
+	}
+}
+
+func TestDecryptLogVersion1(t *testing.T) {
+	aead, err := newLogAEAD(testEncryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	chunk := aead.Seal(nonce, nonce, []byte("legacy\n"), nil)
+	log := []byte(encryptedFileMagicV1)
+	log = binary.BigEndian.AppendUint32(log, uint32(len(chunk)))
+	log = append(log, chunk...)
+
+	var out bytes.Buffer
+	if err := DecryptLog(&out, bytes.NewReader(log), testEncryptionKey); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "legacy\n" {
+		t.Errorf("unexpected decrypted log %q", out.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "v1.log.enc")
+	if err := ioutil.WriteFile(path, log, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenEncryptedFile(path, testEncryptionKey); err == nil {
+		t.Error("expected appending to a version 1 file to fail")
+	}
+}
@@ -0,0 +1,23 @@
+package logger
+
+import "time"
+
+// Entry is the structured form of a single logged message. It is passed to any Writer which also implements Sink, so that
+// sinks which forward logs to remote services have access to each component rather than the composed line of text.
+type Entry struct {
+	// Sequence is a process-wide, monotonically increasing number assigned to each entry when it is logged, which
+	// orders entries whose Times are equal.
+	Sequence uint64
+	Time     time.Time
+	Level    Level
+	Category string
+	// Message is the Message component text once its Formatter has been applied.
+	Message string
+	Fields  []Field
+}
+
+// Sink is implemented by Writers which consume structured Entries. When a Logger's Writer implements Sink, WriteEntry is
+// called instead of writing the composed text, and neither category padding nor grouping are applied.
+type Sink interface {
+	WriteEntry(e Entry) error
+}
@@ -0,0 +1,84 @@
+package logger
+
+import "strings"
+
+// LogErrChain logs err if it is not nil, rendering each error in its chain of causes on its own indented line rather
+// than as one flattened string. Both errors.Unwrap chains and errors.Join trees are followed:
+//
+//	failed to start server
+//	  caused by: failed to load config
+//	  caused by: open config.json: no such file or directory
+//
+// The text of each wrapped error is shown without the text of its cause, where the cause was appended with ": ".
+func (l *Logger) LogErrChain(err error) {
+	if err == nil {
+		return
+	}
+	l.performLazyLog(func() string {
+		err := skipBareWrappers(err)
+		var b strings.Builder
+		b.WriteString(errText(err))
+		renderErrChain(&b, err, "  ")
+		return b.String()
+	}, false, nil)
+}
+
+// renderErrChain writes a "caused by" line for each of the causes of err, recursively. The causes of joined errors are
+// indented further, and the causes of each of those further still, so that each branch can be told apart.
+func renderErrChain(b *strings.Builder, err error, indent string) {
+	causes := unwrapErr(err)
+	lineIndent, childIndent := indent, indent
+	if len(causes) > 1 {
+		lineIndent, childIndent = indent+"  ", indent+"    "
+	}
+	for _, cause := range causes {
+		cause = skipBareWrappers(cause)
+		b.WriteString("\n" + lineIndent + "caused by: " + errText(cause))
+		renderErrChain(b, cause, childIndent)
+	}
+}
+
+// errText returns the text of err without the text of its cause, where err wraps a single cause which it appends with
+// ": ", i.e. fmt.Errorf("failed to load config: %w", err). Joined errors are described by their number of causes.
+func errText(err error) string {
+	text := err.Error()
+	causes := unwrapErr(err)
+	switch {
+	case len(causes) == 1:
+		return strings.TrimSuffix(text, ": "+causes[0].Error())
+	case len(causes) > 1:
+		var texts []string
+		for _, cause := range causes {
+			texts = append(texts, cause.Error())
+		}
+		if text == strings.Join(texts, "\n") {
+			return "multiple errors"
+		}
+	}
+	return text
+}
+
+// skipBareWrappers unwraps err for as long as it wraps a single cause without adding any text of its own, i.e.
+// fmt.Errorf("%w", cause).
+func skipBareWrappers(err error) error {
+	for {
+		causes := unwrapErr(err)
+		if len(causes) != 1 || err.Error() != causes[0].Error() {
+			return err
+		}
+		err = causes[0]
+	}
+}
+
+// unwrapErr returns the errors wrapped by err, supporting both Unwrap() error and Unwrap() []error.
+func unwrapErr(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	case interface{ Unwrap() error }:
+		if cause := e.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	}
+	return nil
+}
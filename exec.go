@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// RunCommand runs cmd, streaming each line it writes to stdout and stderr into the stdout and stderr loggers
+// respectively. Lines are logged under a sub-category named after the command, i.e. a git command run with an EXEC
+// Logger is logged as "EXEC.git". An entry is also logged when the command starts, and when it exits along with its
+// exit_code, to the stderr Logger if it failed. A nil stderr Logger logs everything to stdout. The error returned by
+// cmd.Run is returned.
+func RunCommand(cmd *exec.Cmd, stdout, stderr *Logger) error {
+	if stderr == nil {
+		stderr = stdout
+	}
+	name := filepath.Base(cmd.Path)
+	out := &lineWriter{logger: stdout.subLogger(name)}
+	errOut := &lineWriter{logger: stderr.subLogger(name)}
+	cmd.Stdout, cmd.Stderr = out, errOut
+
+	out.logger.performLog("started", false, []Field{String("args", strings.Join(cmd.Args, " "))})
+	err := cmd.Run()
+	out.flush()
+	errOut.flush()
+
+	exitCode := 0
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	fields := []Field{Int("exit_code", exitCode)}
+	if err != nil {
+		fields = append(fields, String(ErrorKey, err.Error()))
+		errOut.logger.performLog("exited", false, fields)
+		return err
+	}
+	out.logger.performLog("exited", false, fields)
+	return nil
+}
+
+// subLogger returns an unregistered Logger which shares the Logger's settings but logs under the dotted sub-category
+// name, i.e. "EXEC.git".
+func (l *Logger) subLogger(name string) *Logger {
+	category := l.Category
+	category.Name += "." + name
+	l.stateMu.RLock()
+	defer l.stateMu.RUnlock()
+	return &Logger{
+		Category:       category,
+		Timestamp:      l.Timestamp,
+		Message:        l.Message,
+		Order:          l.Order,
+		Level:          l.Level,
+		Writer:         l.Writer,
+		Enabled:        l.Enabled,
+		id:             l.id,
+		scrubRules:     l.scrubRules,
+		maxMessageSize: l.maxMessageSize,
+		fallback:       l.fallback,
+		errorPolicy:    l.errorPolicy,
+		sequenceField:  l.sequenceField,
+		workerField:    l.workerField,
+		preWriteHooks:  l.preWriteHooks,
+		postWriteHooks: l.postWriteHooks,
+		filters:        l.filters,
+		tags:           l.tags,
+	}
+}
+
+// lineWriter logs every complete line written to it as a separate message.
+type lineWriter struct {
+	logger *Logger
+	mu     sync.Mutex
+	buf    []byte
+}
+
+// Write logs each complete line of p, buffering any incomplete line until the rest of it is written.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.logger.performLog(string(bytes.TrimRight(w.buf[:i], "\r")), false, nil)
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush logs any incomplete final line.
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.logger.performLog(string(w.buf), false, nil)
+		w.buf = nil
+	}
+}
@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FlushTimeout is the longest Fatal and Panic wait for queued messages to be written before exiting.
+var FlushTimeout = 5 * time.Second
+
+var (
+	exitHooks   []func()
+	exitHooksMu sync.Mutex
+	// osExit is called by Fatal, and replaced in tests.
+	osExit = os.Exit
+)
+
+// AddExitHook registers a hook which is called by the Fatal and Panic functions before the program exits or panics, once
+// queued messages have been written, i.e. to close sinks or emit a crash report. Hooks are called in the order they were
+// added.
+func AddExitHook(hook func()) {
+	exitHooksMu.Lock()
+	exitHooks = append(exitHooks, hook)
+	exitHooksMu.Unlock()
+}
+
+// Flush blocks until every message queued so far has been written, or until timeout elapses. It reports whether the
+// queue was flushed. The poller must be running for queued messages to be written.
+func Flush(timeout time.Duration) bool {
+	done := make(chan struct{})
+	afterQueued(func() {
+		close(done)
+	})
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// runExitHooks flushes the queue and calls every exit hook.
+func runExitHooks() {
+	Flush(FlushTimeout)
+
+	exitHooksMu.Lock()
+	hooks := exitHooks
+	exitHooksMu.Unlock()
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// Fatal logs the provided message if the Logger is enabled, waits for it to be written, runs the exit hooks and then
+// exits the program with status 1.
+func (l *Logger) Fatal(msg ...interface{}) {
+	l.performLazyLog(lazySprint(msg), false, nil)
+	runExitHooks()
+	osExit(1)
+}
+
+// Fatalf logs the provided message with formatting in the same way as Fatal, then exits the program with status 1.
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.performLazyLog(lazySprintf(format, args), false, nil)
+	runExitHooks()
+	osExit(1)
+}
+
+// Panic logs the provided message if the Logger is enabled, waits for it to be written, runs the exit hooks and then
+// panics with the message.
+func (l *Logger) Panic(msg ...interface{}) {
+	message := sprint(msg...)
+	l.performLog(message, false, nil)
+	runExitHooks()
+	panic(message)
+}
+
+// Panicf logs the provided message with formatting in the same way as Panic, then panics with the message.
+func (l *Logger) Panicf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, resolveLazy(args)...)
+	l.performLog(message, false, nil)
+	runExitHooks()
+	panic(message)
+}
+
+// Fatal logs the provided message with the default FATAL Logger, then exits the program with status 1.
+func Fatal(msg ...interface{}) {
+	DefaultLogger(LevelFatal).Fatal(msg...)
+}
+
+// Fatalf logs the provided message with formatting with the default FATAL Logger, then exits the program with status 1.
+func Fatalf(format string, args ...interface{}) {
+	DefaultLogger(LevelFatal).Fatalf(format, args...)
+}
+
+// Panic logs the provided message with the default FATAL Logger, then panics with the message.
+func Panic(msg ...interface{}) {
+	DefaultLogger(LevelFatal).Panic(msg...)
+}
+
+// Panicf logs the provided message with formatting with the default FATAL Logger, then panics with the message.
+func Panicf(format string, args ...interface{}) {
+	DefaultLogger(LevelFatal).Panicf(format, args...)
+}
@@ -0,0 +1,21 @@
+package logger
+
+import (
+	"expvar"
+	"sync"
+)
+
+var publishExpvarOnce sync.Once
+
+// PublishExpvar publishes logging statistics through expvar under the "logger" variable, so they are included in the
+// /debug/vars output alongside the runtime's memstats. Calling it more than once has no effect.
+func PublishExpvar() {
+	publishExpvarOnce.Do(func() {
+		expvar.Publish("logger", expvar.Func(expvarStats))
+	})
+}
+
+// expvarStats returns the value published by PublishExpvar.
+func expvarStats() interface{} {
+	return Stats()
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldKind determines which of a Field's values is set.
+type fieldKind uint8
+
+const (
+	fieldString fieldKind = iota
+	fieldAny
+	fieldInt
+	fieldUint
+	fieldFloat
+	fieldBool
+	fieldDuration
+)
+
+// Field is a key/value pair attached to an Entry, i.e. the trace_id of the span that was active when the entry was
+// logged. Fields are written after the Message component in text output and are passed to sinks as part of the Entry.
+type Field struct {
+	Key  string
+	kind fieldKind
+	str  string
+	any  interface{}
+	// num holds the value of typed numeric, bool and duration Fields, so that they are encoded without fmt or boxing.
+	num uint64
+}
+
+// String creates a Field with a string value.
+func String(key, value string) Field {
+	return Field{Key: key, kind: fieldString, str: value}
+}
+
+// Any creates a Field with an arbitrary value, which is formatted with fmt when written as text.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, kind: fieldAny, any: value}
+}
+
+// Str creates a Field with a string value. It is equivalent to String.
+func Str(key, value string) Field {
+	return String(key, value)
+}
+
+// Int creates a Field with an int value. Typed Fields such as Int are encoded without fmt or interface boxing, so are
+// preferred over Any when logging at a high rate.
+func Int(key string, value int) Field {
+	return Int64(key, int64(value))
+}
+
+// Int64 creates a Field with an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, kind: fieldInt, num: uint64(value)}
+}
+
+// Uint64 creates a Field with a uint64 value.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, kind: fieldUint, num: value}
+}
+
+// Float64 creates a Field with a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, kind: fieldFloat, num: math.Float64bits(value)}
+}
+
+// Bool creates a Field with a bool value.
+func Bool(key string, value bool) Field {
+	f := Field{Key: key, kind: fieldBool}
+	if value {
+		f.num = 1
+	}
+	return f
+}
+
+// Duration creates a Field with a time.Duration value, which is written as text in the form "1.5s".
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, kind: fieldDuration, num: uint64(value)}
+}
+
+// Value returns the value of the Field.
+func (f Field) Value() interface{} {
+	switch f.kind {
+	case fieldString:
+		return f.str
+	case fieldInt:
+		return int64(f.num)
+	case fieldUint:
+		return f.num
+	case fieldFloat:
+		return math.Float64frombits(f.num)
+	case fieldBool:
+		return f.num == 1
+	case fieldDuration:
+		return time.Duration(f.num)
+	}
+	return f.any
+}
+
+// String returns the value of the Field formatted as text.
+func (f Field) String() string {
+	switch f.kind {
+	case fieldString:
+		return f.str
+	case fieldAny:
+		return fmt.Sprint(f.any)
+	}
+	var scratch [32]byte
+	return string(f.appendValue(scratch[:0]))
+}
+
+// appendValue appends the text of a typed Field's value to dst. It must not be called for string or Any Fields.
+func (f Field) appendValue(dst []byte) []byte {
+	switch f.kind {
+	case fieldInt:
+		return strconv.AppendInt(dst, int64(f.num), 10)
+	case fieldUint:
+		return strconv.AppendUint(dst, f.num, 10)
+	case fieldFloat:
+		return strconv.AppendFloat(dst, math.Float64frombits(f.num), 'g', -1, 64)
+	case fieldBool:
+		return strconv.AppendBool(dst, f.num == 1)
+	case fieldDuration:
+		return append(dst, time.Duration(f.num).String()...)
+	}
+	return dst
+}
+
+// LogFields logs the provided message with fields attached if the Logger is enabled.
+func (l *Logger) LogFields(message string, fields ...Field) {
+	l.performLog(message, false, fields)
+}
+
+// composeFields formats fields as space separated key=value pairs, quoting values which contain spaces, quotes or
+// equals signs.
+func composeFields(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	b := getBuffer()
+	defer putBuffer(b)
+	writeFields(b, fields)
+	return b.String()
+}
+
+// writeFields writes fields to b in the format of composeFields.
+func writeFields(b *bytes.Buffer, fields []Field) {
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		// typed values never need quoting, so are appended directly
+		if f.kind != fieldString && f.kind != fieldAny {
+			var scratch [32]byte
+			b.Write(f.appendValue(scratch[:0]))
+			continue
+		}
+		value := f.String()
+		if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(value)
+	}
+}
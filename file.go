@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// File is a Writer which appends to a file and can reopen its path, so that external log rotation such as logrotate
+// works without copytruncate: once the file has been moved aside, Reopen (or ReopenFiles, or SIGHUP after
+// HandleSignals has been called) starts a new file at the original path, rather than writing to the moved inode
+// forever. Files can also rotate themselves by size, see SetRotation. Files are opened by NewFileLogger and Configure.
+type File struct {
+	mu       sync.Mutex
+	path     string
+	perm     os.FileMode
+	file     *os.File
+	size     int64
+	rotation RotationPolicy
+}
+
+// RotationPolicy determines when a File rotates itself. Once a write would grow the file beyond MaxSize bytes, the file
+// is renamed to "<path>.1" (renaming any existing "<path>.1" to "<path>.2" and so on) and a new file is started at the
+// original path.
+type RotationPolicy struct {
+	// MaxSize is the size in bytes at which the file is rotated. 0 disables rotation.
+	MaxSize int64
+	// MaxBackups is the number of rotated files to keep, the oldest being deleted. 0 deletes the file when it is
+	// rotated.
+	MaxBackups int
+}
+
+// OpenFile opens the file at path for appending, creating it with the provided permissions if necessary.
+func OpenFile(path string, perm os.FileMode) (*File, error) {
+	file, size, err := openAppend(path, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &File{path: path, perm: perm, file: file, size: size}, nil
+}
+
+// openAppend opens the file at path for appending and returns its current size.
+func openAppend(path string, perm os.FileMode) (*os.File, int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, perm)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+// SetRotation sets the RotationPolicy of the file, which applies from the next write.
+func (f *File) SetRotation(policy RotationPolicy) {
+	f.mu.Lock()
+	f.rotation = policy
+	f.mu.Unlock()
+}
+
+// Write appends p to the file, rotating it first if p would grow it beyond the MaxSize of its RotationPolicy.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.rotation.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.rotation.MaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate moves the file aside as the newest backup, deleting the oldest, and starts a new file at the original path.
+func (f *File) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	var err error
+	if f.rotation.MaxBackups > 0 {
+		os.Remove(backupPath(f.path, f.rotation.MaxBackups))
+		for i := f.rotation.MaxBackups - 1; i > 0; i-- {
+			os.Rename(backupPath(f.path, i), backupPath(f.path, i+1))
+		}
+		err = os.Rename(f.path, backupPath(f.path, 1))
+	} else {
+		err = os.Remove(f.path)
+	}
+	file, size, openErr := openAppend(f.path, f.perm)
+	if openErr != nil {
+		// keep the closed file so that subsequent writes fail rather than panic
+		return openErr
+	}
+	f.file, f.size = file, size
+	return err
+}
+
+// backupPath returns the path of the nth rotated file.
+func backupPath(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
+
+// Reopen closes the file and opens its path again, creating a new file if the old one has been moved aside.
+func (f *File) Reopen() error {
+	file, size, err := openAppend(f.path, f.perm)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	old := f.file
+	f.file, f.size = file, size
+	f.mu.Unlock()
+	return old.Close()
+}
+
+// Path returns the path of the file.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// ReopenFiles reopens every Writer of a registered Logger which implements Reopener, including the Writers of
+// MultiSinks, i.e. once logrotate has moved the files aside. Writers shared by several loggers are only reopened once.
+// It is called when the process receives SIGHUP after HandleSignals has been called.
+func ReopenFiles() error {
+	reopened := make(map[interface{}]bool)
+	var errs multiError
+	reopen := func(w io.Writer) {
+		r, ok := w.(Reopener)
+		if !ok {
+			return
+		}
+		if reflect.TypeOf(w).Comparable() {
+			if reopened[w] {
+				return
+			}
+			reopened[w] = true
+		}
+		if err := r.Reopen(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, l := range registeredLoggers() {
+		w := l.currentWriter()
+		if multi, ok := w.(*MultiSink); ok {
+			multi.each(func(t multiTarget) error {
+				reopen(t.writer)
+				return nil
+			})
+			continue
+		}
+		reopen(w)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
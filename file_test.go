@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	f, err := OpenFile(path, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.SetRotation(RotationPolicy{MaxSize: 10, MaxBackups: 2})
+
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n", "five\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string]string{
+		path:        "four\nfive\n",
+		path + ".1": "three\n",
+		path + ".2": "one\ntwo\n",
+	}
+	for p, content := range expected {
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s contains %q, expected %q", p, data, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups to be kept, got %v", err)
+	}
+}
+
+func TestConfigureRotation(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "rotated.log")
+	configPath := filepath.Join(dir, "logging.json")
+	config := `{"loggers": [{"category": "ROTATED", "outputs": [{"path": "` + logPath + `", "max_size": 64, "max_backups": 1}]}]}`
+	if err := ioutil.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loggers, err := Configure(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := loggers["ROTATED"]
+	defer RemoveLogger(l)
+	for i := 0; i < 4; i++ {
+		l.Log(strings.Repeat("x", 20))
+	}
+	Flush(time.Second)
+
+	if _, err := os.Stat(logPath + ".1"); err != nil {
+		t.Errorf("expected the file to be rotated: %s", err)
+	}
+}
+
+func TestConfigureRotationRequiresFile(t *testing.T) {
+	_, err := ApplyConfig(Config{Loggers: []LoggerConfig{{
+		Category: "ROTATED",
+		Outputs:  []OutputConfig{{Path: "stdout", MaxSize: 64}},
+	}}})
+	if err == nil {
+		t.Fatal("expected an error for a rotated stdout output")
+	}
+}
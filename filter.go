@@ -0,0 +1,31 @@
+package logger
+
+// FilterFunc reports whether an entry should be written. Filters see the entry once it has been composed, redacted and
+// truncated, so they can suppress entries based on their content.
+type FilterFunc func(e Entry) bool
+
+// AddFilter adds a filter to the Logger, i.e. to drop health check request logs without disabling the whole Logger:
+//
+//	Incoming.AddFilter(func(e logger.Entry) bool {
+//		return !strings.Contains(e.Message, "/healthz")
+//	})
+//
+// An entry is only written if every filter returns true. Suppressed entries are counted by Dropped.
+func (l *Logger) AddFilter(filter FilterFunc) {
+	l.filters = append(l.filters, filter)
+}
+
+// ClearFilters removes all of the Logger's filters.
+func (l *Logger) ClearFilters() {
+	l.filters = nil
+}
+
+// filter reports whether an entry passes all of the Logger's filters.
+func (l *Logger) filter(e Entry) bool {
+	for _, f := range l.filters {
+		if !f(e) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// VerbosityFlag is a flag.Value which controls which loggers are enabled from the command line. A numeric value is
+// passed to SetEnabledByID, i.e. -v=3, a range of IDs is passed to SetEnabledByIDRange, i.e. -v=2-5, a comma separated
+// list of IDs is passed to SetEnabledByIDs, i.e. -v=0,2,5, the name of a verbosity preset is passed to Verbosity, i.e.
+// -v=debug, and any other value is treated as a comma separated list of categories to enable with SetEnabledByCategory,
+// i.e. -log=INCOMING,OUTGOING. It can be registered in one line:
+//
+//	flag.Var(new(logger.VerbosityFlag), "v", "log verbosity, either a preset, a logger ID or a list of categories")
+type VerbosityFlag struct {
+	value string
+}
+
+// String returns the value the flag was last set to.
+func (f *VerbosityFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+// Set applies the value to the registered loggers.
+func (f *VerbosityFlag) Set(value string) error {
+	value = strings.TrimSpace(value)
+	if id, err := strconv.Atoi(value); err == nil {
+		SetEnabledByID(id)
+		f.value = value
+		return nil
+	}
+	if min, max, ok := parseIDRange(value); ok {
+		SetEnabledByIDRange(min, max)
+		f.value = value
+		return nil
+	}
+	if ids, ok := parseIDList(value); ok {
+		SetEnabledByIDs(ids...)
+		f.value = value
+		return nil
+	}
+	if isVerbosityPreset(value) {
+		f.value = value
+		return Verbosity(value)
+	}
+
+	var categories []string
+	for _, c := range strings.Split(value, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			categories = append(categories, c)
+		}
+	}
+	if len(categories) == 0 {
+		return errors.New("expected a logger ID or a list of categories")
+	}
+	SetEnabledByCategory(true, categories...)
+	f.value = value
+	return nil
+}
+
+// parseIDRange parses a range of logger IDs in the form min-max, i.e. "2-5".
+func parseIDRange(value string) (int, int, bool) {
+	i := strings.IndexByte(value, '-')
+	if i <= 0 {
+		return 0, 0, false
+	}
+	min, err := strconv.Atoi(strings.TrimSpace(value[:i]))
+	if err != nil {
+		return 0, 0, false
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(value[i+1:]))
+	if err != nil {
+		return 0, 0, false
+	}
+	return min, max, true
+}
+
+// parseIDList parses a comma separated list of logger IDs, i.e. "0,2,5".
+func parseIDList(value string) ([]int, bool) {
+	var ids []int
+	for _, s := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// categoryGroups are the named groups of categories defined with DefineCategoryGroup, keyed by lower case name.
+var categoryGroups = struct {
+	mu     sync.RWMutex
+	groups map[string][]string
+}{
+	groups: make(map[string][]string),
+}
+
+// DefineCategoryGroup defines (or redefines) a named group of categories, i.e. DefineCategoryGroup("network",
+// "INCOMING", "OUTGOING", "DNS"), so that they can be enabled or disabled together with SetEnabledByGroup without
+// knowing every individual logger. Categories are case sensitive and may be glob patterns as accepted by path.Match,
+// i.e. "HTTP_*". A group of a single category acts as an alias for it. Group names are not case sensitive, and defining
+// a group without categories removes it.
+func DefineCategoryGroup(name string, categories ...string) {
+	categoryGroups.mu.Lock()
+	defer categoryGroups.mu.Unlock()
+	if len(categories) == 0 {
+		delete(categoryGroups.groups, strings.ToLower(name))
+		return
+	}
+	categoryGroups.groups[strings.ToLower(name)] = append([]string(nil), categories...)
+}
+
+// CategoryGroup returns the categories of the named group, and whether the group has been defined.
+func CategoryGroup(name string) ([]string, bool) {
+	categoryGroups.mu.RLock()
+	defer categoryGroups.mu.RUnlock()
+	categories, ok := categoryGroups.groups[strings.ToLower(name)]
+	return append([]string(nil), categories...), ok
+}
+
+// SetEnabledByGroup enables or disables all loggers with Category Names which match the categories of the named groups,
+// i.e. SetEnabledByGroup(false, "network"). An error is returned if any of the groups has not been defined, in which
+// case no loggers are changed.
+func SetEnabledByGroup(enabled bool, groups ...string) error {
+	var categories []string
+	for _, name := range groups {
+		group, ok := CategoryGroup(name)
+		if !ok {
+			return fmt.Errorf("undefined category group %q", name)
+		}
+		categories = append(categories, group...)
+	}
+	SetEnabledByCategory(enabled, categories...)
+	return nil
+}
+
+// ClearCategoryGroups removes every group defined with DefineCategoryGroup. Loggers keep their current state.
+func ClearCategoryGroups() {
+	categoryGroups.mu.Lock()
+	categoryGroups.groups = make(map[string][]string)
+	categoryGroups.mu.Unlock()
+}
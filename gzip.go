@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+	"time"
+)
+
+// GzipWriter is a Writer which gzip-compresses everything written to it on the fly before writing it to an underlying
+// Writer, i.e. a file, for long-running services which generate large text logs. The compressed stream is flushed at
+// a regular interval, so that the underlying Writer always holds a decompressible prefix of the log, which is at most
+// one interval behind. Close must be called to write the end of the stream before the program terminates.
+type GzipWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+	gz     *gzip.Writer
+	dirty  bool
+
+	exitCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewGzipWriter creates a GzipWriter which writes to w, flushing the compressed stream every flushInterval (default
+// of 5 seconds).
+func NewGzipWriter(w io.Writer, flushInterval time.Duration) *GzipWriter {
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+	g := &GzipWriter{
+		writer: w,
+		gz:     gzip.NewWriter(w),
+		exitCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	go g.run(flushInterval)
+	return g
+}
+
+func (g *GzipWriter) run(interval time.Duration) {
+	defer close(g.doneCh)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.Flush()
+		case <-g.exitCh:
+			return
+		}
+	}
+}
+
+// Write compresses p into the stream.
+func (g *GzipWriter) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.dirty = true
+	return g.gz.Write(p)
+}
+
+// Flush writes any data which has been compressed but not yet written to the underlying Writer, ending the current
+// flush boundary.
+func (g *GzipWriter) Flush() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.dirty {
+		return nil
+	}
+	g.dirty = false
+	return g.gz.Flush()
+}
+
+// Reopen ends the current stream and reopens the underlying Writer if it implements Reopener, starting a new stream in
+// the reopened file, so that a GzipWriter can be used with log rotation.
+func (g *GzipWriter) Reopen() error {
+	reopener, ok := g.writer.(Reopener)
+	if !ok {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.gz.Close(); err != nil {
+		return err
+	}
+	if err := reopener.Reopen(); err != nil {
+		return err
+	}
+	g.gz.Reset(g.writer)
+	g.dirty = false
+	return nil
+}
+
+// Close stops the background flushing and writes the end of the stream. The underlying Writer is left open, as it may
+// be shared, so must be closed separately.
+func (g *GzipWriter) Close() error {
+	close(g.exitCh)
+	<-g.doneCh
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.gz.Close()
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// heartbeat is the state of the periodic heartbeat.
+var heartbeat struct {
+	mu   sync.Mutex
+	stop func()
+}
+
+// StartHeartbeat logs a "heartbeat" entry with the provided Logger every interval, so that liveness can be monitored
+// from the log stream alone. Each entry has the process uptime, the number of goroutines, the bytes of allocated heap
+// objects and the number of completed GC cycles as fields, i.e. "heartbeat uptime=2h0m0s goroutines=42
+// heap_alloc=8388608 num_gc=120". The uptime and interval follow the Clock. Any previous heartbeat is stopped.
+func StartHeartbeat(l *Logger, interval time.Duration) {
+	StopHeartbeat()
+	stop := every(interval, func() {
+		logHeartbeat(l)
+	})
+	heartbeat.mu.Lock()
+	heartbeat.stop = stop
+	heartbeat.mu.Unlock()
+}
+
+// StopHeartbeat stops logging the periodic heartbeat.
+func StopHeartbeat() {
+	heartbeat.mu.Lock()
+	if heartbeat.stop != nil {
+		heartbeat.stop()
+		heartbeat.stop = nil
+	}
+	heartbeat.mu.Unlock()
+}
+
+// logHeartbeat logs a single heartbeat entry with the Logger.
+func logHeartbeat(l *Logger) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	l.LogFields("heartbeat",
+		Duration("uptime", now().Sub(elapsedStart()).Round(time.Second)),
+		Int("goroutines", runtime.NumGoroutine()),
+		Uint64("heap_alloc", mem.HeapAlloc),
+		Uint64("num_gc", uint64(mem.NumGC)),
+	)
+}
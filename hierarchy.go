@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// hierarchy holds the settings applied to dotted Category Names, such as "net.http", and inherited by their descendants.
+var hierarchy struct {
+	mu      sync.RWMutex
+	enabled map[string]bool
+	levels  map[string]Level
+}
+
+// SetEnabledByHierarchy enables or disables the loggers with each of the provided dotted Category Names along with all
+// of their descendants, in the style of log4j logger hierarchies: disabling "net.http" disables "net.http" and
+// "net.http.client" but not "net.httpd". A setting on a more specific category overrides one inherited from its
+// ancestors, and loggers created later inherit the settings of their ancestors when they are created.
+func SetEnabledByHierarchy(enabled bool, categories ...string) {
+	hierarchy.mu.Lock()
+	if hierarchy.enabled == nil {
+		hierarchy.enabled = make(map[string]bool)
+	}
+	for _, c := range categories {
+		hierarchy.enabled[c] = enabled
+	}
+	hierarchy.mu.Unlock()
+	applyHierarchyToAll()
+}
+
+// SetLevelByHierarchy sets the Level of the loggers with each of the provided dotted Category Names along with all of
+// their descendants, inheriting in the same way as SetEnabledByHierarchy.
+func SetLevelByHierarchy(lvl Level, categories ...string) {
+	hierarchy.mu.Lock()
+	if hierarchy.levels == nil {
+		hierarchy.levels = make(map[string]Level)
+	}
+	for _, c := range categories {
+		hierarchy.levels[c] = lvl
+	}
+	hierarchy.mu.Unlock()
+	applyHierarchyToAll()
+}
+
+// ClearHierarchy removes all settings made by SetEnabledByHierarchy and SetLevelByHierarchy. Loggers keep their current
+// state.
+func ClearHierarchy() {
+	hierarchy.mu.Lock()
+	hierarchy.enabled = nil
+	hierarchy.levels = nil
+	hierarchy.mu.Unlock()
+}
+
+// applyHierarchyToAll applies the hierarchy settings to every registered Logger.
+func applyHierarchyToAll() {
+	for _, l := range registeredLoggers() {
+		applyHierarchy(l)
+	}
+}
+
+// applyHierarchy applies the settings of the Logger's own category, or failing that of its nearest configured ancestor.
+func applyHierarchy(l *Logger) {
+	hierarchy.mu.RLock()
+	defer hierarchy.mu.RUnlock()
+	if c, ok := nearestAncestor(l.Category.Name, func(c string) bool {
+		_, ok := hierarchy.enabled[c]
+		return ok
+	}); ok {
+		l.SetEnabled(hierarchy.enabled[c])
+	}
+	if c, ok := nearestAncestor(l.Category.Name, func(c string) bool {
+		_, ok := hierarchy.levels[c]
+		return ok
+	}); ok {
+		l.SetLevel(hierarchy.levels[c])
+	}
+}
+
+// nearestAncestor returns the most specific of category and its dotted ancestors for which configured returns true.
+func nearestAncestor(category string, configured func(c string) bool) (string, bool) {
+	for c := category; ; {
+		if configured(c) {
+			return c, true
+		}
+		i := strings.LastIndex(c, ".")
+		if i < 0 {
+			return "", false
+		}
+		c = c[:i]
+	}
+}
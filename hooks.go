@@ -0,0 +1,86 @@
+package logger
+
+import "sync"
+
+// PreWriteHook is called with each entry before it is composed into text, after the Message Formatter has been applied
+// but before redaction and truncation. Hooks may modify the entry, i.e. to annotate it with additional Fields.
+type PreWriteHook func(l *Logger, e *Entry)
+
+// PostWriteHook is called with each entry after it has been written, along with any error returned by the Writer.
+// Post-write hooks are called concurrently by the goroutine of each Writer, so must be safe for concurrent use and must
+// not log to a Logger themselves.
+type PostWriteHook func(l *Logger, e Entry, err error)
+
+var (
+	preWriteHooks  []PreWriteHook
+	postWriteHooks []PostWriteHook
+	hooksMu        sync.RWMutex
+)
+
+// AddPreWriteHook registers a hook which is called before every Logger composes an entry. Global hooks are called
+// before any Logger specific hooks.
+func AddPreWriteHook(hook PreWriteHook) {
+	hooksMu.Lock()
+	preWriteHooks = append(preWriteHooks, hook)
+	hooksMu.Unlock()
+}
+
+// AddPostWriteHook registers a hook which is called after every Logger writes an entry. Global hooks are called before
+// any Logger specific hooks.
+func AddPostWriteHook(hook PostWriteHook) {
+	hooksMu.Lock()
+	postWriteHooks = append(postWriteHooks, hook)
+	hooksMu.Unlock()
+}
+
+// ClearHooks removes all globally registered hooks. Logger specific hooks are unaffected.
+func ClearHooks() {
+	hooksMu.Lock()
+	preWriteHooks = nil
+	postWriteHooks = nil
+	hooksMu.Unlock()
+}
+
+// AddPreWriteHook registers a hook which is called before the Logger composes an entry.
+func (l *Logger) AddPreWriteHook(hook PreWriteHook) {
+	l.preWriteHooks = append(l.preWriteHooks, hook)
+}
+
+// AddPostWriteHook registers a hook which is called after the Logger writes an entry.
+func (l *Logger) AddPostWriteHook(hook PostWriteHook) {
+	l.postWriteHooks = append(l.postWriteHooks, hook)
+}
+
+// runPreWriteHooks calls the global and then the Logger's pre-write hooks with the entry, returning the hooked entry.
+// The entry is only copied to the heap if there are hooks which may keep a pointer to it.
+func (l *Logger) runPreWriteHooks(e Entry) Entry {
+	hooksMu.RLock()
+	global := preWriteHooks
+	hooksMu.RUnlock()
+	if len(global) == 0 && len(l.preWriteHooks) == 0 {
+		return e
+	}
+
+	hooked := e
+	for _, hook := range global {
+		hook(l, &hooked)
+	}
+	for _, hook := range l.preWriteHooks {
+		hook(l, &hooked)
+	}
+	return hooked
+}
+
+// runPostWriteHooks calls the global and then the Logger's post-write hooks with the written entry.
+func (l *Logger) runPostWriteHooks(e Entry, err error) {
+	hooksMu.RLock()
+	global := postWriteHooks
+	hooksMu.RUnlock()
+
+	for _, hook := range global {
+		hook(l, e, err)
+	}
+	for _, hook := range l.postWriteHooks {
+		hook(l, e, err)
+	}
+}
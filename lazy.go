@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// LazyValue is a message, or part of one, which is only computed if it is going to be written. It is computed at most
+// once, however many times it is written, and is safe for concurrent use. Any func() string passed to a Logx function
+// is treated in the same way, so expensive messages can be built without first checking whether the Logger is enabled:
+//
+//	Debug.Log(func() string { return dumpState() })
+//	Debug.Logf("state: %s", logger.Lazy(dumpState))
+//
+// A LazyValue can also be used as the value of a Field, i.e. Any("state", Lazy(dumpState)), in which case it is
+// computed when the entry is composed on the goroutine which logged it, or by the first sink which encodes it.
+type LazyValue struct {
+	once    sync.Once
+	compute func() string
+	value   string
+}
+
+// Lazy creates a LazyValue which is computed by f.
+func Lazy(f func() string) *LazyValue {
+	return &LazyValue{compute: f}
+}
+
+// String computes the value on the first call and returns the cached result thereafter.
+func (v *LazyValue) String() string {
+	v.once.Do(func() {
+		v.value = v.compute()
+	})
+	return v.value
+}
+
+// MarshalText implements encoding.TextMarshaler, so that the value is encoded as a string by JSON encoders.
+func (v *LazyValue) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// lazySprint returns a function which formats msg in the manner of fmt.Sprint when called.
+func lazySprint(msg []interface{}) func() string {
+	return func() string {
+		return sprint(msg...)
+	}
+}
+
+// lazySprintf returns a function which formats args according to format in the manner of fmt.Sprintf when called.
+func lazySprintf(format string, args []interface{}) func() string {
+	return func() string {
+		return fmt.Sprintf(format, resolveLazy(args)...)
+	}
+}
+
+// sprint formats msg in the manner of fmt.Sprint, computing any lazy values. A single string is returned as it is,
+// without formatting.
+func sprint(msg ...interface{}) string {
+	if len(msg) == 1 {
+		if s, ok := msg[0].(string); ok {
+			return s
+		}
+	}
+	return fmt.Sprint(resolveLazy(msg)...)
+}
+
+// resolveLazy returns args with any func() string or LazyValue values replaced by their result. args is only copied if
+// it contains a lazy value.
+func resolveLazy(args []interface{}) []interface{} {
+	resolved := args
+	for i, arg := range args {
+		var value string
+		switch f := arg.(type) {
+		case func() string:
+			value = f()
+		case *LazyValue:
+			value = f.String()
+		default:
+			continue
+		}
+		if &resolved[0] == &args[0] {
+			resolved = append([]interface{}(nil), args...)
+		}
+		resolved[i] = value
+	}
+	return resolved
+}
@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLazyComputedOnce(t *testing.T) {
+	calls := 0
+	v := Lazy(func() string {
+		calls++
+		return "state"
+	})
+
+	if v.String() != "state" || v.String() != "state" {
+		t.Fatalf("expected the computed value, got %q", v.String())
+	}
+	data, err := json.Marshal(Any("state", v).Value())
+	if err != nil || string(data) != `"state"` {
+		t.Fatalf("expected the value to be encoded as a JSON string, got %s (%v)", data, err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the value to be computed once, got %d", calls)
+	}
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Level is the severity of the entries written by a Logger. Levels are ordered, so sinks and filters can compare them to
+// a minimum Level, i.e. only forwarding LevelError and above to an alerting sink.
+type Level int
+
+const (
+	// LevelDebug is used for verbose diagnostic information.
+	LevelDebug Level = iota - 1
+	// LevelInfo is used for general information and is the zero value Level.
+	LevelInfo
+	// LevelWarning is used for unexpected but recoverable conditions.
+	LevelWarning
+	// LevelError is used for failures which require attention.
+	LevelError
+	// LevelFatal is used for failures which the application cannot recover from.
+	LevelFatal
+)
+
+var levelNames = map[Level]string{
+	LevelDebug:   "DEBUG",
+	LevelInfo:    "INFO",
+	LevelWarning: "WARNING",
+	LevelError:   "ERROR",
+	LevelFatal:   "FATAL",
+}
+
+// String returns the upper case name of the Level.
+func (lvl Level) String() string {
+	if name, ok := levelNames[lvl]; ok {
+		return name
+	}
+	return "LEVEL(" + strconv.Itoa(int(lvl)) + ")"
+}
+
+// ParseLevel returns the Level matching the provided name, ignoring case. Common abbreviations such as WARN, ERR and CRIT
+// are also accepted. The second return value is false if the name does not correspond to a Level.
+func ParseLevel(name string) (Level, bool) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "DEBUG", "TRACE":
+		return LevelDebug, true
+	case "INFO":
+		return LevelInfo, true
+	case "WARNING", "WARN":
+		return LevelWarning, true
+	case "ERROR", "ERR":
+		return LevelError, true
+	case "FATAL", "CRITICAL", "CRIT", "PANIC":
+		return LevelFatal, true
+	}
+	return LevelInfo, false
+}
+
+// levelFromCategory determines the default Level of a new Logger from its Category Name, i.e. an "ERROR" Logger has a
+// Level of LevelError. Categories which do not name a Level default to LevelInfo.
+func levelFromCategory(category string) Level {
+	lvl, _ := ParseLevel(category)
+	return lvl
+}
@@ -0,0 +1,113 @@
+// Package logflags provides ready-made pflag and cobra flags which configure the logger package, for command line
+// programs built on github.com/spf13/cobra:
+//
+//	logflags.Bind(rootCmd)
+//
+// adds the persistent flags --log-level, --log-format and --log-file to rootCmd, and applies them before any command
+// runs.
+package logflags
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jemgunay/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Flags holds the values of the logging flags.
+type Flags struct {
+	// Level is the minimum Level of the loggers to enable, i.e. "warn" disables every Logger with a Level below
+	// LevelWarning. An empty Level leaves all loggers as they are.
+	Level string
+	// Format is "text", "color" or "json". An empty Format leaves each Logger's format as it is.
+	Format string
+	// File is the path of a file which every Logger appends to, or "stdout" or "stderr". An empty File leaves each
+	// Logger's Writer as it is.
+	File string
+}
+
+// AddFlags registers --log-level, --log-format and --log-file with fs.
+func (f *Flags) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&f.Level, "log-level", f.Level, "minimum level of the loggers to enable (debug, info, warn, error, fatal)")
+	fs.StringVar(&f.Format, "log-format", f.Format, "log output format (text, color, json)")
+	fs.StringVar(&f.File, "log-file", f.File, "file to write logs to, or stdout or stderr")
+}
+
+// Apply configures every registered Logger according to the flag values.
+func (f *Flags) Apply() error {
+	var (
+		minLevel logger.Level
+		encoder  logger.EncoderFunc
+		writer   io.Writer
+	)
+	if f.Level != "" {
+		lvl, ok := logger.ParseLevel(f.Level)
+		if !ok {
+			return fmt.Errorf("invalid --log-level %q", f.Level)
+		}
+		minLevel = lvl
+	}
+	if f.Format != "" {
+		enc, ok := logger.ParseEncoder(f.Format)
+		if !ok {
+			return fmt.Errorf("invalid --log-format %q", f.Format)
+		}
+		encoder = enc
+	}
+	switch strings.ToLower(f.File) {
+	case "":
+	case "stdout":
+		writer = os.Stdout
+	case "stderr":
+		writer = os.Stderr
+	default:
+		file, err := os.OpenFile(f.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("invalid --log-file: %s", err)
+		}
+		writer = file
+	}
+
+	for _, l := range logger.Loggers() {
+		if f.Level != "" {
+			l.SetEnabled(l.CurrentLevel() >= minLevel)
+		}
+		w := l.Writer
+		if writer != nil {
+			w = writer
+		}
+		if encoder != nil {
+			multi := logger.MultiWriter()
+			multi.AddEncoded(w, encoder, logger.LevelDebug)
+			w = multi
+		}
+		l.Writer = w
+	}
+	return nil
+}
+
+// Bind adds the logging flags to the persistent flags of cmd and applies them before cmd, or any of its subcommands,
+// runs. Any existing PersistentPreRunE or PersistentPreRun of cmd is called afterwards.
+func Bind(cmd *cobra.Command) *Flags {
+	f := &Flags{}
+	f.AddFlags(cmd.PersistentFlags())
+
+	preRunE, preRun := cmd.PersistentPreRunE, cmd.PersistentPreRun
+	cmd.PersistentPreRunE = func(c *cobra.Command, args []string) error {
+		if err := f.Apply(); err != nil {
+			return err
+		}
+		if preRunE != nil {
+			return preRunE(c, args)
+		}
+		if preRun != nil {
+			preRun(c, args)
+		}
+		return nil
+	}
+	return f
+}
@@ -2,24 +2,33 @@
 package logger
 
 import (
-	"fmt"
+	"bytes"
 	"io"
 	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
 var (
 	loggers          = make(map[*Logger]bool)
+	loggersMu        sync.RWMutex
 	categoryPadding  = true
 	categoryGrouping = true
+	multilineIndent  = true
 
-	// BufferSize determines the size of the buffered channel used to queue messages when a logger is set to use its buffer.
+	// BufferSize determines the size of the queue used to pass messages to the poller, rounded up to a power of two.
 	BufferSize      = 1024
-	bufferEnabled   = false
+	bufferEnabled   int32
 	highestLoggerID = -1
-	logQueue        = make(chan queueItem)
-	logQueueBuffer  = make(chan queueItem, BufferSize)
+	sequence        uint64
+	logQueue        = newRingQueue(BufferSize)
 	exitCh          = make(chan struct{})
 
 	// Internal is an internal logger for logging debug and error related info.
@@ -28,62 +37,320 @@ var (
 
 // queueItem is used to push a new message onto the write queue
 type queueItem struct {
+	logger   *Logger
 	writer   io.Writer
 	category Category
-	message  string
+	entry    Entry
+	// timestamp is the Logger's Timestamp when the message was logged, so that it is not read while ApplyConfig is
+	// changing it.
+	timestamp Timestamp
+	// previous is the time of the Logger's previous message in nanoseconds since the Unix epoch, from which the Delta
+	// is measured.
+	previous int64
+	newline  bool
+	order    ComponentOrder
+	// ungrouped writes the Category even if category grouping would otherwise hide it.
+	ungrouped bool
+	// status marks an update to the Logger's status line, or the entry which finalises it.
+	status statusMode
+	// barrier is called by the poller in place of writing, once every message queued before it has been written.
+	barrier func()
+	// encoder and fanout are set on the copy of a message written to each Writer of a MultiSink, see dispatchMulti.
+	encoder EncoderFunc
+	fanout  *fanout
 }
 
-// startPoller attempts to receive from both the standard queue, the buffered queue and exit channel. This serialises
-// all logging writes.
+// StartPoller starts the poller, which receives messages from the queue and the priority queue until it is stopped.
+// Messages are then dispatched to a queue for each Writer, which is written by its own goroutine so that a slow Writer
+// does not delay the messages of other Writers. Writes to each Writer remain serialised and in order.
 func StartPoller() {
 	go func() {
 		for {
+			// priority messages are always dispatched first
+			dispatchPriority()
+
+			// dispatch every message in the queue before waiting for more
+			if queueItem, ok := logQueue.pop(); ok {
+				if queueItem.barrier != nil {
+					dispatchPriority()
+				}
+				dispatch(queueItem)
+				continue
+			}
+
 			select {
-			// receive and write a message from the queue
-			case queueItem := <-logQueue:
-				performWrite(queueItem)
+			// receive and dispatch a priority message
+			case queueItem := <-logQueuePriority:
+				dispatch(queueItem)
 
-				// receive and write a message from the queue
-			case queueItem := <-logQueueBuffer:
-				performWrite(queueItem)
+				// a message has been pushed onto the queue
+			case <-logQueue.notify:
 
 				// stop polling for logs to write
 			case <-exitCh:
+				stopWriterQueues()
 				return
 			}
 		}
 	}()
 }
 
+// layoutMu guards the package-level settings which control how lines are composed, i.e. categoryPadding and
+// maxCategorySize, so that they can be changed while messages are being written.
+var layoutMu sync.RWMutex
+
+var maxCategorySize int
+
+// fixedCategoryWidth is the width of the category column set by SetCategoryWidth, or 0 for dynamic padding.
+var fixedCategoryWidth int
+
+// lineWrapping is set by SetLineWrapping, and fixedWrapWidth is the width set by SetWrapWidth, or 0 to wrap messages
+// at the width of the terminal being written to.
 var (
-	maxCategorySize  int
-	previousCategory string
+	lineWrapping   bool
+	fixedWrapWidth int
 )
 
 // performWrite formats messages to align timestamps and group messages based on category depending on whether these
-// features have been enabled.
-func performWrite(queueItem queueItem) {
-	padding := ""
+// features have been enabled. previousCategory is the Category Name last written to the same Writer, for grouping.
+func performWrite(queueItem queueItem, previousCategory *string) {
+	if queueItem.barrier != nil {
+		queueItem.barrier()
+		return
+	}
+	if queueItem.status == statusUpdate {
+		updateStatus(queueItem)
+		return
+	}
+	// the copies of a message written to a MultiSink's Writers were transformed once when they were dispatched
+	if queueItem.fanout == nil {
+		entry, ok := applyTransforms(queueItem.entry)
+		if !ok {
+			queueItem.logger.drop()
+			return
+		}
+		queueItem.entry = entry
+	}
+
+	start := time.Now()
+
+	// the line is composed into a pooled buffer the first time it is needed, and written from it directly
+	var line *bytes.Buffer
+	defer func() {
+		if line != nil {
+			putBuffer(line)
+		}
+	}()
+	composeOnce := func() []byte {
+		if line == nil {
+			line = getBuffer()
+			composeLine(line, queueItem, *previousCategory)
+			line.WriteByte('\n')
+		}
+		return line.Bytes()
+	}
+	write := func(w io.Writer, encoder EncoderFunc) error {
+		return queueItem.logger.retryWrite(func() error {
+			if encoder != nil {
+				_, err := w.Write(encoder(queueItem.entry))
+				return err
+			}
+			// sinks receive the structured entry rather than the composed text
+			if sink, ok := w.(Sink); ok {
+				return sink.WriteEntry(queueItem.entry)
+			}
+			return writeAroundStatus(w, queueItem, composeOnce())
+		})
+	}
+
+	var err error
+	if multi, ok := queueItem.writer.(*MultiSink); ok {
+		err = multi.writeEntry(queueItem.entry, write)
+	} else {
+		err = write(queueItem.writer, queueItem.encoder)
+	}
+	observeWriteDuration(queueItem.category.Name, start)
+	if err != nil {
+		queueItem.logger.handleWriteError(err, queueItem.writer, func() string {
+			return strings.TrimSuffix(string(composeOnce()), "\n")
+		})
+	}
+	if line != nil {
+		*previousCategory = queueItem.category.Name
+	}
+
+	// a message written to a MultiSink is only finished once it has been written to each of its Writers
+	if queueItem.fanout != nil {
+		var last bool
+		if last, err = queueItem.fanout.done(err); !last {
+			return
+		}
+	}
+	if err == nil {
+		atomic.StoreInt64(&queueItem.logger.lastWrite, now().UnixNano())
+	}
+	queueItem.logger.runPostWriteHooks(queueItem.entry, err)
+	publishViewer(queueItem.entry)
+	emitStatsD(queueItem.entry)
+}
+
+// composeLine composes a queued message from its Category, Timestamp and Entry, applying category padding, grouping and
+// multi-line indentation, and writes it to b without a trailing newline. previousCategory is the Category Name last
+// written to the same Writer. The line is composed in a single pass, encoding the Timestamp and the Entry's Fields
+// directly rather than through intermediate strings.
+func composeLine(b *bytes.Buffer, queueItem queueItem, previousCategory string) {
+	layoutMu.RLock()
+	fixedWidth, padded, maxWidth := fixedCategoryWidth, categoryPadding, maxCategorySize
+	grouped, indented, wrapped := categoryGrouping, multilineIndent, lineWrapping
+	layoutMu.RUnlock()
+
 	currentCategory := queueItem.category.Compose()
+	categoryWidth := textWidth(currentCategory)
 
-	// pad log categories so that all timestamps are aligned
-	if categoryPadding {
-		padding = strings.Repeat(" ", maxCategorySize-len(currentCategory)+1)
+	padding := 0
+	switch {
+	case fixedWidth > 0:
+		// truncate or pad log categories to the fixed column width
+		if categoryWidth > fixedWidth {
+			currentCategory = truncateCategory(queueItem.category, currentCategory, fixedWidth)
+			categoryWidth = textWidth(currentCategory)
+		}
+		padding = fixedWidth - categoryWidth + 1
+	case padded:
+		// pad log categories so that all timestamps are aligned, allowing for unregistered loggers with longer categories
+		padding = 1
+		if width := maxWidth - categoryWidth + 1; width > 1 {
+			padding = width
+		}
+	case currentCategory != "":
+		padding++
 	}
-	if queueItem.category.Name != "" && categoryPadding == false {
-		padding += " "
+
+	// write the Timestamp first if the Logger's ComponentOrder requires it
+	timestampWidth := 0
+	if queueItem.order == TimestampFirst {
+		timestampWidth = writeTimestamp(b, queueItem)
 	}
 
 	// group logs by category
-	if categoryGrouping && previousCategory == queueItem.category.Name {
-		currentCategory = strings.Repeat(" ", len(currentCategory))
+	if grouped && !deterministic && !queueItem.ungrouped && previousCategory == queueItem.category.Name {
+		writeSpaces(b, categoryWidth)
+	} else {
+		b.WriteString(currentCategory)
+	}
+	writeSpaces(b, padding)
+	if queueItem.order != TimestampFirst {
+		timestampWidth = writeTimestamp(b, queueItem)
+	}
+	indent := categoryWidth + padding + timestampWidth
+
+	// align continuation lines of multi-line messages under the Message component
+	lineIndent := 0
+	if indented {
+		lineIndent = indent
+	}
+
+	// soft-wrap long lines at the width of the terminal
+	wrap := 0
+	if wrapped && queueItem.status != statusUpdate {
+		if width := wrapWidth(queueItem.writer); width-indent >= minWrapWidth {
+			wrap = width - indent
+		}
+	}
+	if wrap == 0 {
+		writeMessage(b, queueItem.entry, queueItem.newline, lineIndent)
+		return
 	}
-	queueItem.message = currentCategory + padding + queueItem.message
 
-	// write message
-	fmt.Fprintln(queueItem.writer, queueItem.message)
+	// wrapping needs the whole of the message and its fields, which are composed first
+	composed := getBuffer()
+	writeMessage(composed, queueItem.entry, queueItem.newline, 0)
+	message := composed.String()
+	putBuffer(composed)
 
-	previousCategory = queueItem.category.Name
+	// a trailing newline is not followed by an indent
+	body := strings.TrimSuffix(message, "\n")
+	written := 0
+	for {
+		i := strings.IndexByte(body[written:], '\n')
+		if i < 0 {
+			break
+		}
+		writeWrapped(b, body[written:written+i], wrap, wrap, indent)
+		b.WriteByte('\n')
+		writeSpaces(b, lineIndent)
+		written += i + 1
+	}
+	writeWrapped(b, body[written:], wrap, wrap, indent)
+	b.WriteString(message[len(body):])
+}
+
+// writeTimestamp writes the Timestamp text of a queued message to b, followed by its Delta if enabled and a space. It
+// returns the width of the text in columns, as the Timestamp may contain wide characters or colour escape sequences.
+func writeTimestamp(b *bytes.Buffer, queueItem queueItem) int {
+	t := &queueItem.timestamp
+	start := b.Len()
+	if t.Formatter != nil {
+		b.WriteString(t.composeAt(queueItem.entry.Time))
+	} else {
+		var scratch [64]byte
+		b.Write(t.appendAt(scratch[:0], queueItem.entry.Time))
+	}
+	b.WriteByte(' ')
+	if t.Delta && queueItem.status != statusUpdate {
+		b.WriteString(composeDelta(queueItem.entry.Time, queueItem.previous))
+		b.WriteByte(' ')
+	}
+
+	written := b.Bytes()[start:]
+	for _, c := range written {
+		if c < 0x20 || c >= utf8.RuneSelf {
+			return textWidth(string(written))
+		}
+	}
+	return len(written)
+}
+
+// writeMessage writes the Message component of an entry followed by its Fields, and a newline if requested. Each
+// continuation line of a multi-line Message is indented by indent columns, other than a trailing newline.
+func writeMessage(b *bytes.Buffer, e Entry, newline bool, indent int) {
+	message := e.Message
+	trailing := len(e.Fields) == 0 && !newline && strings.HasSuffix(message, "\n")
+	if trailing {
+		message = message[:len(message)-1]
+	}
+	for indent > 0 {
+		i := strings.IndexByte(message, '\n')
+		if i < 0 {
+			break
+		}
+		b.WriteString(message[:i+1])
+		writeSpaces(b, indent)
+		message = message[i+1:]
+	}
+	b.WriteString(message)
+
+	if len(e.Fields) > 0 {
+		b.WriteByte(' ')
+		writeFields(b, e.Fields)
+	}
+	if trailing || newline {
+		b.WriteByte('\n')
+	}
+}
+
+// spaces is written in chunks by writeSpaces.
+const spaces = "                                                                "
+
+// writeSpaces writes n spaces to b.
+func writeSpaces(b *bytes.Buffer, n int) {
+	for n > len(spaces) {
+		b.WriteString(spaces)
+		n -= len(spaces)
+	}
+	if n > 0 {
+		b.WriteString(spaces[:n])
+	}
 }
 
 // FormatterFunc is used to pass a string manipulating function to a Logger's Category, Timestamp or Message in order to
@@ -101,45 +368,177 @@ var (
 	}
 )
 
-// Category is the Logger component which is written to output first. It is used to categorise logged messages based on
-// their intended purpose/meaning (if the Name property is set), i.e. INFO, WARNING, ERROR, etc.
+// ComponentOrder is the order in which the Category and Timestamp components of a Logger are written. The Message
+// component is always written last, so that continuation lines and fields follow it.
+type ComponentOrder uint8
+
+const (
+	// CategoryFirst writes the Category before the Timestamp, i.e. "[INFO] 15:04:05 message". It is the default.
+	CategoryFirst ComponentOrder = iota
+	// TimestampFirst writes the Timestamp before the Category, i.e. "15:04:05 [INFO] message". Category padding is
+	// then written after the Category, so that messages remain aligned.
+	TimestampFirst
+)
+
+// Category is the Logger component which is written to output first by default. It is used to categorise logged
+// messages based on their intended purpose/meaning (if the Name property is set), i.e. INFO, WARNING, ERROR, etc.
 type Category struct {
 	Formatter FormatterFunc
 	Name      string
+	// Icon is a short symbol or emoji written before the formatted Name, i.e. "🔥 [ERROR]", so that interleaved output
+	// can be scanned at a glance.
+	Icon string
 }
 
-// Compose constructs the Category component text if a Name has been provided. Otherwise, an empty Category text is
-// returned.
+// Compose constructs the Category component text if a Name has been provided, preceded by the Icon if one has been
+// set. Otherwise, an empty Category text is returned.
 func (c *Category) Compose() string {
-	if c.Name == "" || c.Formatter == nil {
-		return c.Name
+	text := c.Name
+	if c.Name != "" && c.Formatter != nil {
+		text = c.Formatter(c.Name)
+	}
+	if c.Icon == "" {
+		return text
+	}
+	if text == "" {
+		return c.Icon
 	}
-	return c.Formatter(c.Name)
+	return c.Icon + " " + text
 }
 
-// Timestamp is the Logger component which is written to output after the Category but before the Message. The Format
-// determines the layout of the formatted timestamp (default of 06/01/02 15:04:05.00000).
+// Timestamp is the Logger component which is written to output after the Category (or before it with the TimestampFirst
+// Order) but before the Message. The Format determines the layout of the formatted timestamp (default of 06/01/02
+// 15:04:05.00000). Timestamps are rendered in the process-local time zone unless UseUTC is set, or a Location is
+// provided.
 type Timestamp struct {
 	Format    string
 	Formatter FormatterFunc
+	// UseUTC renders timestamps in UTC, taking precedence over Location.
+	UseUTC bool
+	// Location is the time zone timestamps are rendered in (default of time.Local).
+	Location *time.Location
+	// Delta writes the time since the Logger's previous message after the timestamp, i.e. "(+12ms)", so that slow steps
+	// stand out when reading sequential logs. The first message of a Logger has a delta of "(+0s)".
+	Delta bool
+}
+
+// Timestamp Format presets, which can be used in place of a layout. The Unix presets render the number of seconds,
+// milliseconds or nanoseconds since the Unix epoch, which cannot be expressed as a layout and do not depend on the time
+// zone. TimestampElapsed renders the time since the process started (or since the Clock was set with SetClock) as
+// minutes, seconds and milliseconds, i.e. "+00:03.412", which is easier to read than the time of day for CLI tools and
+// test runs. Hours are added once an hour has elapsed, i.e. "+1:02:03.412".
+const (
+	TimestampRFC3339     = time.RFC3339
+	TimestampRFC3339Nano = time.RFC3339Nano
+	TimestampUnix        = "unix"
+	TimestampUnixMilli   = "unix_milli"
+	TimestampUnixNano    = "unix_nano"
+	TimestampElapsed     = "elapsed"
+)
+
+// timestampPresets are the names of the Timestamp Format presets which are accepted by config files, and the Formats
+// they select.
+var timestampPresets = map[string]string{
+	"rfc3339":          TimestampRFC3339,
+	"rfc3339nano":      TimestampRFC3339Nano,
+	TimestampUnix:      TimestampUnix,
+	TimestampUnixMilli: TimestampUnixMilli,
+	TimestampUnixNano:  TimestampUnixNano,
+	TimestampElapsed:   TimestampElapsed,
 }
 
 // Compose constructs the Timestamp component text if a Format has been provided. Otherwise, an empty Timestamp text is
 // returned.
 func (t *Timestamp) Compose() string {
+	return t.composeAt(now())
+}
+
+// composeAt constructs the Timestamp component text for the provided time.
+func (t *Timestamp) composeAt(ts time.Time) string {
 	if t.Format == "" {
 		return t.Format
 	}
-
-	ts := time.Now()
-	datetime := ts.Format(t.Format)
-
+	datetime := string(t.appendAt(nil, ts))
 	if t.Formatter == nil {
 		return datetime
 	}
 	return t.Formatter(datetime)
 }
 
+// appendAt appends the text of the provided time in the Timestamp's Format to dst, without applying its Formatter.
+func (t *Timestamp) appendAt(dst []byte, ts time.Time) []byte {
+	switch t.Format {
+	case "":
+		return dst
+	case TimestampUnix:
+		return strconv.AppendInt(dst, ts.Unix(), 10)
+	case TimestampUnixMilli:
+		return strconv.AppendInt(dst, ts.UnixNano()/int64(time.Millisecond), 10)
+	case TimestampUnixNano:
+		return strconv.AppendInt(dst, ts.UnixNano(), 10)
+	case TimestampElapsed:
+		return appendElapsed(dst, ts.Sub(elapsedStart()))
+	}
+	if t.UseUTC {
+		ts = ts.UTC()
+	} else if t.Location != nil {
+		ts = ts.In(t.Location)
+	}
+	return ts.AppendFormat(dst, t.Format)
+}
+
+// composeDelta constructs the Delta text of a message logged at ts, where previous is the time of the Logger's previous
+// message in nanoseconds since the Unix epoch, or 0 if there was none. Deltas are rounded to a precision which suits
+// their size, i.e. "(+450µs)", "(+12ms)" or "(+1.23s)".
+func composeDelta(ts time.Time, previous int64) string {
+	var d time.Duration
+	if previous != 0 {
+		d = time.Duration(ts.UnixNano() - previous)
+	}
+	switch {
+	case d >= time.Minute:
+		d = d.Round(time.Second)
+	case d >= time.Second:
+		d = d.Round(10 * time.Millisecond)
+	case d >= time.Millisecond:
+		d = d.Round(time.Millisecond)
+	default:
+		d = d.Round(time.Microsecond)
+	}
+	if d < 0 {
+		return "(" + d.String() + ")"
+	}
+	return "(+" + d.String() + ")"
+}
+
+// appendElapsed appends d to b in the TimestampElapsed format, i.e. "+00:03.412" or "+1:02:03.412".
+func appendElapsed(b []byte, d time.Duration) []byte {
+	sign := byte('+')
+	if d < 0 {
+		sign = '-'
+		d = -d
+	}
+	millis := int64(d / time.Millisecond)
+	b = append(b, sign)
+	if hours := millis / 3600000; hours > 0 {
+		b = strconv.AppendInt(b, hours, 10)
+		b = append(b, ':')
+	}
+	b = appendPadded(b, millis/60000%60, 2)
+	b = append(b, ':')
+	b = appendPadded(b, millis/1000%60, 2)
+	b = append(b, '.')
+	return appendPadded(b, millis%1000, 3)
+}
+
+// appendPadded appends n to b, padded with leading zeros to width digits.
+func appendPadded(b []byte, n int64, width int) []byte {
+	for digits := len(strconv.FormatInt(n, 10)); digits < width; digits++ {
+		b = append(b, '0')
+	}
+	return strconv.AppendInt(b, n, 10)
+}
+
 // Message is the is the Logger component which is written to output last, following the Timestamp Component.
 type Message struct {
 	Formatter FormatterFunc
@@ -154,13 +553,18 @@ func (m *Message) Compose(message string) string {
 }
 
 // Logger is a logger which is designed to output one specific type of logging information. Output messages are composed
-// out of the Category, Timestamp and Message components in that order before they are written to the Writer. The Logger
-// can be enabled/disabled - when disabled, any calls to a Logx function will be silently ignored. The Logger also
-// counts how many messages is has logged.
+// out of the Category, Timestamp and Message components in the Order (Category first by default) before they are
+// written to the Writer. The Logger can be enabled/disabled - when disabled, any calls to a Logx function will be
+// silently ignored. The Logger also counts how many messages is has logged. The Level describes the severity of the
+// messages logged, which sinks can use to filter entries. Enabled and Level may be set directly before the Logger is
+// used, but once it is logging they must be changed with SetEnabled (or Enable and Disable) and SetLevel, which are safe
+// to call while other goroutines are logging.
 type Logger struct {
 	Category  Category
 	Timestamp Timestamp
 	Message   Message
+	Level     Level
+	Order     ComponentOrder
 
 	Writer         io.Writer
 	Enabled        bool
@@ -168,19 +572,41 @@ type Logger struct {
 	splunkEnabled  bool
 	counterEnabled bool
 	counterName    string
-	count          int
+	count          int64
+	dropped        int64
+	lastWrite      int64
+	lastEntry      int64
+	sampler        Sampler
+	rateLimiter    *rateLimiter
+	duplicates     *duplicateSuppressor
+	scrubRules     ScrubRules
+	maxMessageSize int
+	fallback       io.Writer
+	owned          io.Writer
+	onceKeys       sync.Map
+	errorPolicy    ErrorPolicy
+	sequenceField  bool
+	workerField    bool
+	preWriteHooks  []PreWriteHook
+	postWriteHooks []PostWriteHook
+	filters        []FilterFunc
+	tags           []string
+	// renamed is set by SetCategoryName until the Logger's next message is queued.
+	renamed int32
+	// stateMu guards the settings which may be changed once the Logger is in use, i.e. by ApplyConfig: Enabled, Level,
+	// Writer, Timestamp, tags, maxMessageSize and sequenceField.
+	stateMu sync.RWMutex
 }
 
 // NewLogger creates a new logger given an io.Writer to log to, a category to display before the timestamp and a flag to
-// determine whether the logger is enabled by default. A pointer to this Logger is then returned.
+// determine whether the logger is enabled by default. The Level is derived from the category where it names one (i.e.
+// "ERROR" or "WARNING"), otherwise it defaults to LevelInfo. A pointer to this Logger is then returned.
 func NewLogger(handle io.Writer, category string, enabled bool) *Logger {
-	highestLoggerID++
-
 	// create new logger
 	newLogger := Logger{
 		Writer:  handle,
 		Enabled: enabled,
-		id:      highestLoggerID,
+		Level:   levelFromCategory(category),
 		Category: Category{
 			Name:      category,
 			Formatter: SquareBracketWrapper,
@@ -195,8 +621,13 @@ func NewLogger(handle io.Writer, category string, enabled bool) *Logger {
 	}
 
 	// store reference to logger & reset prefix padding
+	loggersMu.Lock()
+	highestLoggerID++
+	newLogger.id = highestLoggerID
 	loggers[&newLogger] = true
-	SetCategoryPadding(categoryPadding)
+	loggersMu.Unlock()
+	applyHierarchy(&newLogger)
+	updateCategoryPadding()
 
 	return &newLogger
 }
@@ -205,114 +636,525 @@ func NewLogger(handle io.Writer, category string, enabled bool) *Logger {
 func AddLogger(newLoggers ...*Logger) {
 	for _, newLogger := range newLoggers {
 		// store reference to logger & reset prefix padding
+		loggersMu.Lock()
 		highestLoggerID++
 		newLogger.id = highestLoggerID
 		loggers[newLogger] = true
-		SetCategoryPadding(categoryPadding)
+		loggersMu.Unlock()
+		applyHierarchy(newLogger)
+		updateCategoryPadding()
+	}
+}
+
+// RemoveLogger removes Logger(s) from the logger system so that they are no longer affected by package-level settings,
+// no longer count towards category padding and can be garbage collected once unreferenced. This allows short-lived
+// components to create scoped loggers without permanently growing the registry. A removed Logger is disabled, so any
+// further messages logged with it are ignored.
+func RemoveLogger(oldLoggers ...*Logger) {
+	loggersMu.Lock()
+	for _, l := range oldLoggers {
+		delete(loggers, l)
+		l.SetEnabled(false)
 	}
+	loggersMu.Unlock()
+	updateCategoryPadding()
 }
 
 // SetCategoryPadding is used to enable or disable padding after all Categories to align all Timestamps. This is also
 // called internally to reset the padding mechanism when a new logger is created.
 func SetCategoryPadding(enabled bool) {
-	categoryPadding = enabled
-
-	maxCategorySize = 0
+	// determine the maximum amount of padding required to align timestamps
+	var tempMax, categorySize int
 	if enabled {
-		// determine the maximum amount of padding required to align timestamps
-		var tempMax, categorySize int
+		loggersMu.RLock()
 		for l := range loggers {
-			categorySize = len(l.Category.Compose())
+			categorySize = textWidth(l.Category.Compose())
 
 			if categorySize > tempMax {
 				tempMax = categorySize
 			}
 		}
-		maxCategorySize = tempMax
+		loggersMu.RUnlock()
+	}
+
+	layoutMu.Lock()
+	categoryPadding = enabled
+	maxCategorySize = tempMax
+	layoutMu.Unlock()
+}
+
+// updateCategoryPadding recomputes the category padding once loggers have been added, removed or renamed.
+func updateCategoryPadding() {
+	layoutMu.RLock()
+	enabled := categoryPadding
+	layoutMu.RUnlock()
+	SetCategoryPadding(enabled)
+}
+
+// SetCategoryWidth fixes the width of the category column to n terminal columns, as an alternative to padding every
+// category to the width of the longest, so that timestamps stay aligned in the same column as loggers are added at
+// runtime. Shorter categories are padded and longer categories are truncated to fit, i.e. [AUTHENTICATION] becomes
+// [AUTHEN] with a width of 8. The fixed width takes precedence over SetCategoryPadding. A width of 0 or less restores
+// dynamic padding.
+func SetCategoryWidth(n int) {
+	if n < 0 {
+		n = 0
+	}
+	layoutMu.Lock()
+	fixedCategoryWidth = n
+	layoutMu.Unlock()
+}
+
+// truncateCategory returns the composed text of c with its Name truncated so that the text fits within width columns.
+// Text added by the Category Formatter and Icon is kept where possible, i.e. the brackets of SquareBracketWrapper, and
+// is only truncated itself if it is wider than width.
+func truncateCategory(c Category, composed string, width int) string {
+	overhead := textWidth(composed) - textWidth(c.Name)
+	if overhead >= 0 && overhead < width {
+		c.Name = truncateWidth(c.Name, width-overhead)
+		if text := c.Compose(); textWidth(text) <= width {
+			return text
+		}
+	}
+	return truncateWidth(composed, width)
+}
+
+// SetMultilineIndent enables or disables the indentation of multi-line messages. When enabled (the default), every line
+// after the first is indented to align with the start of the Message component, so that stack traces and dumps remain
+// visually grouped with the entry they belong to.
+func SetMultilineIndent(enabled bool) {
+	layoutMu.Lock()
+	multilineIndent = enabled
+	layoutMu.Unlock()
+}
+
+// SetLineWrapping enables or disables soft-wrapping of long messages. When enabled, messages written to a terminal are
+// wrapped at the terminal's width, with each wrapped line indented to align with the start of the Message component,
+// rather than being hard-wrapped by the terminal back at the first column. Lines are broken at spaces where possible.
+// The width is queried as each message is written, so resizing the terminal applies to the next message. Messages
+// written to anything other than a terminal are not wrapped unless a width is set with SetWrapWidth. Wrapping is
+// disabled by default.
+func SetLineWrapping(enabled bool) {
+	layoutMu.Lock()
+	lineWrapping = enabled
+	layoutMu.Unlock()
+}
+
+// SetWrapWidth sets the width in columns at which messages are wrapped when line wrapping is enabled, for every Writer
+// rather than only terminals, i.e. to wrap messages written to files or pipes. A width of 0 or less restores wrapping at
+// the width of the terminal.
+func SetWrapWidth(n int) {
+	if n < 0 {
+		n = 0
 	}
+	layoutMu.Lock()
+	fixedWrapWidth = n
+	layoutMu.Unlock()
+}
+
+// minWrapWidth is the fewest columns left for the Message component for which messages are wrapped, so that a narrow
+// terminal does not wrap every word onto a line of its own.
+const minWrapWidth = 20
+
+// wrapWidth returns the width in columns at which messages written to w are wrapped, or 0 if they are not wrapped.
+// Batched messages are wrapped at the width of the Writer of their batch.
+func wrapWidth(w io.Writer) int {
+	layoutMu.RLock()
+	fixed := fixedWrapWidth
+	layoutMu.RUnlock()
+	if fixed > 0 {
+		return fixed
+	}
+	if batch, ok := w.(*writeBatch); ok {
+		w = batch.writer
+	}
+	if f, ok := w.(*os.File); ok {
+		return terminalWidth(f)
+	}
+	return 0
 }
 
 // SetCategoryGrouping enables or disables category grouping. This means that if a number of messages are output with
 // the same Category Name, only the first message contains the Category Name prefix.
 func SetCategoryGrouping(enabled bool) {
+	layoutMu.Lock()
 	categoryGrouping = enabled
+	layoutMu.Unlock()
 }
 
 // performLog formats & writes a log message to one of the logging queues depending on whether buffered logging has been
 // enabled. Each of the Logx functions depend on performLog.
-func (l *Logger) performLog(message string, newline bool) {
-	if l.Enabled == false {
+func (l *Logger) performLog(message string, newline bool, fields []Field) {
+	l.performLazyLog(func() string { return message }, newline, fields)
+}
+
+// performLazyLog is performLog for a message which is only composed once it is known to be needed, so that formatting
+// is skipped entirely when the Logger is disabled, or the message is dropped by sampling or rate limiting.
+func (l *Logger) performLazyLog(compose func() string, newline bool, fields []Field) {
+	var (
+		message  string
+		composed bool
+	)
+	composeOnce := func() string {
+		if !composed {
+			message = compose()
+			composed = true
+		}
+		return message
+	}
+
+	if !l.admit(composeOnce, fields) {
+		// messages which are not written are not given a Sequence number, so that written messages have no gaps
+		recordRecent(func() Entry {
+			entry := l.sequencedEntry(0, composeOnce(), fields)
+			l.sanitiseEntry(&entry)
+			return entry
+		})
 		return
 	}
+	entry := l.enqueueStatus(composeOnce(), newline, fields, statusNone)
+	recordRecent(func() Entry {
+		return entry
+	})
+}
+
+// admit reports whether a message should be written, i.e. that the Logger is enabled and the message is not dropped by
+// duplicate suppression, sampling or rate limiting.
+func (l *Logger) admit(message func() string, fields []Field) bool {
+	if !l.IsEnabled() {
+		return false
+	}
+	if l.duplicates != nil && l.duplicates.suppress(l, message(), fields) {
+		l.drop()
+		return false
+	}
+	if l.sampler != nil && !l.sampler.Sample() {
+		l.drop()
+		return false
+	}
+	if l.rateLimiter != nil && !l.rateLimiter.allow(l) {
+		l.drop()
+		return false
+	}
+	return true
+}
+
+// enqueue composes a message and sends it to be written, bypassing the Logger's sampling and rate limiting.
+func (l *Logger) enqueue(message string, newline bool, fields []Field) {
+	l.enqueueStatus(message, newline, fields, statusNone)
+}
 
+// enqueueStatus composes a message and sends it to be written in the same way as enqueue, marked with its effect on
+// the Logger's status line. The composed entry is returned, even if it is dropped by the Logger's filter.
+func (l *Logger) enqueueStatus(message string, newline bool, fields []Field, status statusMode) Entry {
 	// compose message
-	message = l.Timestamp.Compose() + " " + l.Message.Compose(message)
-	if newline {
-		message += "\n"
+	entry := l.runPreWriteHooks(l.newEntry(message, fields))
+	l.sanitiseEntry(&entry)
+	if !l.filter(entry) {
+		l.drop()
+		return entry
 	}
 
-	// send message to be written
+	// send message to be written, which is composed into text by the goroutine of its Writer
+	l.stateMu.RLock()
+	writer, timestamp := l.Writer, l.Timestamp
+	l.stateMu.RUnlock()
 	newMsg := queueItem{
-		writer:   l.Writer,
-		category: l.Category,
-		message:  message,
+		logger:    l,
+		writer:    writer,
+		category:  l.Category,
+		timestamp: timestamp,
+		entry:     entry,
+		previous:  atomic.SwapInt64(&l.lastEntry, entry.Time.UnixNano()),
+		newline:   newline,
+		order:     l.Order,
+		status:    status,
+	}
+	if atomic.LoadInt32(&l.renamed) == 1 && atomic.CompareAndSwapInt32(&l.renamed, 1, 0) {
+		newMsg.ungrouped = true
 	}
 
-	l.count++
-	if bufferEnabled {
-		logQueueBuffer <- newMsg
-		return
+	atomic.AddInt64(&l.count, 1)
+	l.incrementCounter()
+	switch {
+	case deterministic:
+		writeSynchronously(newMsg)
+	case buffered():
+		if !isPriority(newMsg) || !enqueuePriority(newMsg) {
+			enqueueBuffered(newMsg)
+		}
+	default:
+		// without buffering, wait for the poller to receive the message
+		logQueue.waitConsumed(logQueue.pushWait(newMsg))
+	}
+	return entry
+}
+
+// newEntry creates the structured form of a message logged now, applying the Message Formatter, and gives it the next
+// Sequence number.
+func (l *Logger) newEntry(message string, fields []Field) Entry {
+	return l.sequencedEntry(atomic.AddUint64(&sequence, 1), message, fields)
+}
+
+// sequencedEntry creates the structured form of a message logged now with the provided Sequence number. A Sequence of 0
+// is used for messages which are not written, which are given no "seq" field.
+func (l *Logger) sequencedEntry(seq uint64, message string, fields []Field) Entry {
+	l.stateMu.RLock()
+	level, sequenceField := l.Level, l.sequenceField && seq != 0
+	l.stateMu.RUnlock()
+
+	entry := Entry{
+		Sequence: seq,
+		Time:     now(),
+		Level:    level,
+		Category: l.Category.Name,
+		Message:  l.Message.Compose(message),
+		Fields:   fields,
 	}
-	logQueue <- newMsg
+	scoped := scopeFields()
+	if sequenceField || l.workerField || len(scoped) > 0 {
+		prefix := make([]Field, 0, 2+len(scoped)+len(fields))
+		if sequenceField {
+			prefix = append(prefix, Uint64("seq", entry.Sequence))
+		}
+		if l.workerField {
+			prefix = append(prefix, String(WorkerKey, currentWorker()))
+		}
+		prefix = append(prefix, scoped...)
+		entry.Fields = append(prefix, fields...)
+	}
+	return entry
+}
+
+// sanitiseEntry applies redaction and truncation to an entry before it is written.
+func (l *Logger) sanitiseEntry(e *Entry) {
+	l.stateMu.RLock()
+	maxMessageSize := l.maxMessageSize
+	l.stateMu.RUnlock()
+
+	redactEntry(e, l.scrubRules)
+	e.Message = truncateMessage(e.Message, maxMessageSize)
 }
 
-// SetBuffered enables or disables logging via a buffered channel. When enabled, the caller of Logx functions does not
-// block. When disabled, the caller is blocked until the message is received.
+// SetBuffered enables or disables buffered logging. When enabled, the caller of Logx functions does not block unless
+// the queue is full. When disabled, the caller is blocked until the message is received by the poller.
 func SetBuffered(useBuffer bool) {
-	bufferEnabled = useBuffer
+	enabled := int32(0)
+	if useBuffer {
+		enabled = 1
+	}
+	atomic.StoreInt32(&bufferEnabled, enabled)
+}
+
+// buffered reports whether buffered logging is enabled.
+func buffered() bool {
+	return atomic.LoadInt32(&bufferEnabled) == 1
 }
 
 // Log logs the provided message if the Logger is enabled.
 func (l *Logger) Log(msg ...interface{}) {
-	l.performLog(fmt.Sprint(msg...), false)
+	l.performLazyLog(lazySprint(msg), false, nil)
 }
 
 // Logf logs the provided message with formatting if the Logger is enabled.
 func (l *Logger) Logf(format string, args ...interface{}) {
-	l.performLog(fmt.Sprintf(format, args...), false)
+	l.performLazyLog(lazySprintf(format, args), false, nil)
 }
 
 // Logln logs the provided message followed by a new line if the Logger is enabled.
 func (l *Logger) Logln(msg ...interface{}) {
-	l.performLog(fmt.Sprint(msg...), true)
+	l.performLazyLog(lazySprint(msg), true, nil)
+}
+
+// AddWriter attaches an additional Writer to the Logger which only receives entries with a Level of minLevel or above.
+// If the Logger's Writer is not already a MultiSink, it is replaced by one which writes every entry to the original
+// Writer. Each Writer has its own queue, so a slow Writer does not delay the others.
+func (l *Logger) AddWriter(w io.Writer, minLevel Level) {
+	l.stateMu.Lock()
+	defer l.stateMu.Unlock()
+	multi, ok := l.Writer.(*MultiSink)
+	if !ok {
+		multi = MultiWriter()
+		if l.Writer != nil {
+			multi.Add(l.Writer)
+		}
+		l.Writer = multi
+	}
+	multi.AddLevel(w, minLevel)
+}
+
+// SetFallbackWriter sets the Writer which messages are written to when the Logger's Writer returns an error under the
+// ErrorFallback policy, i.e. when a disk fills or a socket breaks. Messages are written to the fallback as composed text,
+// even if the Logger's Writer is a Sink. A nil Writer restores the default of os.Stderr.
+func (l *Logger) SetFallbackWriter(w io.Writer) {
+	l.fallback = w
+}
+
+// fallbackWriter returns the Writer used when the Logger's Writer fails.
+func (l *Logger) fallbackWriter() io.Writer {
+	if l.fallback == nil {
+		return os.Stderr
+	}
+	return l.fallback
+}
+
+// SetSequenceField enables or disables writing each entry's Sequence number as a "seq" field, so that entries can be
+// reordered correctly even when their timestamps collide at the configured precision.
+func (l *Logger) SetSequenceField(enabled bool) {
+	l.stateMu.Lock()
+	l.sequenceField = enabled
+	l.stateMu.Unlock()
 }
 
 // Enable enables the logger.
 func (l *Logger) Enable() {
-	l.Enabled = true
+	l.SetEnabled(true)
 }
 
 // Disable disables the logger, meaning any logged messages are silently ignored.
 func (l *Logger) Disable() {
-	l.Enabled = false
+	l.SetEnabled(false)
+}
+
+// SetEnabled enables or disables the Logger. It is safe to call while other goroutines are logging.
+func (l *Logger) SetEnabled(enabled bool) {
+	l.stateMu.Lock()
+	l.Enabled = enabled
+	l.stateMu.Unlock()
+}
+
+// IsEnabled reports whether the Logger is enabled. It is safe to call while the Logger is being enabled or disabled.
+func (l *Logger) IsEnabled() bool {
+	l.stateMu.RLock()
+	defer l.stateMu.RUnlock()
+	return l.Enabled
+}
+
+// SetLevel sets the Level of the messages logged by the Logger. It is safe to call while other goroutines are logging.
+func (l *Logger) SetLevel(lvl Level) {
+	l.stateMu.Lock()
+	l.Level = lvl
+	l.stateMu.Unlock()
 }
 
-// Count returns the number of messages logged by the Logger.
+// CurrentLevel returns the Level of the messages logged by the Logger. It is safe to call while the Level is being
+// changed.
+func (l *Logger) CurrentLevel() Level {
+	l.stateMu.RLock()
+	defer l.stateMu.RUnlock()
+	return l.Level
+}
+
+// currentWriter returns the Logger's Writer. It is safe to call while ApplyConfig is replacing the Writer.
+func (l *Logger) currentWriter() io.Writer {
+	l.stateMu.RLock()
+	defer l.stateMu.RUnlock()
+	return l.Writer
+}
+
+// SetCategoryName renames the Logger's Category, i.e. to relabel a logger created per tenant or connection. The name is
+// changed while the registry is locked, so that package-level functions which select loggers by category see either
+// the old or the new name, and category padding is then recomputed for the new name. The Logger's next message is
+// written with its Category even if category grouping would otherwise hide it. Messages which are already queued are
+// written with the previous name.
+func (l *Logger) SetCategoryName(name string) {
+	loggersMu.Lock()
+	l.Category.Name = name
+	loggersMu.Unlock()
+	atomic.StoreInt32(&l.renamed, 1)
+	updateCategoryPadding()
+}
+
+// Count returns the number of messages logged by the Logger. It is safe to call while the Logger is in use.
 func (l *Logger) Count() int {
-	return l.count
+	return int(atomic.LoadInt64(&l.count))
+}
+
+// ResetCount sets the number of messages logged and dropped by the Logger to zero.
+func (l *Logger) ResetCount() {
+	atomic.StoreInt64(&l.count, 0)
+	atomic.StoreInt64(&l.dropped, 0)
+}
+
+// Dropped returns the number of messages which were logged while the Logger was enabled, but were discarded before
+// being written.
+func (l *Logger) Dropped() int64 {
+	return atomic.LoadInt64(&l.dropped)
+}
+
+// LastWrite returns the time the Logger last wrote a message successfully, or the zero Time if it has not.
+func (l *Logger) LastWrite() time.Time {
+	nanos := atomic.LoadInt64(&l.lastWrite)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// drop records that a message logged by the Logger has been discarded.
+func (l *Logger) drop() {
+	atomic.AddInt64(&l.dropped, 1)
 }
 
 // SetEnabledByCategory enables or disables all loggers with Category Names which match the list of categories provided,
 // i.e. SetEnabledByCategory(false, "INCOMING", "OUTGOING") would disable both INCOMING and OUTGOING loggers if they
-// exist. The categories are case sensitive.
+// exist. Categories may also be glob patterns as accepted by path.Match, i.e. "HTTP_*" matches every category beginning
+// with HTTP_. The categories are case sensitive.
 func SetEnabledByCategory(enabled bool, categories ...string) {
+	loggersMu.RLock()
+	defer loggersMu.RUnlock()
+	for l := range loggers {
+		for _, c := range categories {
+			if matchCategory(c, l.Category.Name) {
+				l.SetEnabled(enabled)
+			}
+		}
+	}
+}
+
+// SetCategoryIcon sets the Category Icon of all registered loggers with Category Names which match the list of
+// categories provided, i.e. SetCategoryIcon("🔥", "ERROR", "FATAL"). Categories may also be glob patterns as accepted by
+// path.Match. An empty icon removes the Icon. Category padding is recomputed to account for the width of the icon.
+func SetCategoryIcon(icon string, categories ...string) {
+	loggersMu.RLock()
 	for l := range loggers {
 		for _, c := range categories {
-			if l.Category.Name == c {
-				l.Enabled = enabled
+			if matchCategory(c, l.Category.Name) {
+				l.Category.Icon = icon
 			}
 		}
 	}
+	loggersMu.RUnlock()
+	updateCategoryPadding()
+}
+
+// SetEnabledByCategoryRegexp enables or disables all loggers with Category Names which match the regular expression
+// expr, i.e. SetEnabledByCategoryRegexp(true, "^(HTTP|GRPC)_") would enable every HTTP_ and GRPC_ logger. An error is
+// returned if expr is not a valid regular expression.
+func SetEnabledByCategoryRegexp(enabled bool, expr string) error {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return err
+	}
+
+	loggersMu.RLock()
+	defer loggersMu.RUnlock()
+	for l := range loggers {
+		if re.MatchString(l.Category.Name) {
+			l.SetEnabled(enabled)
+		}
+	}
+	return nil
+}
+
+// matchCategory reports whether a Category Name matches pattern, which is either a category or a glob pattern. Invalid
+// patterns only match a category equal to the pattern itself.
+func matchCategory(pattern, category string) bool {
+	if pattern == category {
+		return true
+	}
+	matched, err := path.Match(pattern, category)
+	return err == nil && matched
 }
 
 // SetEnabledByID is used to enable all loggers which have an ID of loggerID or below, and to disable all other loggers.
@@ -320,8 +1162,36 @@ func SetEnabledByCategory(enabled bool, categories ...string) {
 // created (the Internal logger) will have an ID of 0, and the ID will increment by 1 for every other logger created.
 // A negative loggerID will disable all loggers.
 func SetEnabledByID(loggerID int) {
+	loggersMu.RLock()
+	defer loggersMu.RUnlock()
+	for l := range loggers {
+		l.SetEnabled(l.id <= loggerID)
+	}
+}
+
+// SetEnabledByIDRange enables all loggers which have an ID between min and max inclusive, and disables all other
+// loggers, i.e. SetEnabledByIDRange(3, 5) enables the third to fifth loggers created after the Internal logger. A min
+// greater than max disables all loggers.
+func SetEnabledByIDRange(min, max int) {
+	loggersMu.RLock()
+	defer loggersMu.RUnlock()
+	for l := range loggers {
+		l.SetEnabled(l.id >= min && l.id <= max)
+	}
+}
+
+// SetEnabledByIDs enables all loggers which have one of the provided IDs, and disables all other loggers, i.e.
+// SetEnabledByIDs(0, 2, 5). No IDs disables all loggers.
+func SetEnabledByIDs(ids ...int) {
+	selected := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		selected[id] = true
+	}
+
+	loggersMu.RLock()
+	defer loggersMu.RUnlock()
 	for l := range loggers {
-		l.Enabled = l.id <= loggerID
+		l.SetEnabled(selected[l.id])
 	}
 }
 
@@ -329,24 +1199,47 @@ func SetEnabledByID(loggerID int) {
 // server is also shut down.
 func StopPoller() {
 	exitCh <- struct{}{}
+	StopViewer()
 }
 
 // Log logs the provided message if the Logger is enabled.
 func Log(logger *Logger, msg ...interface{}) {
-	logger.performLog(fmt.Sprint(msg...), false)
+	logger.performLazyLog(lazySprint(msg), false, nil)
 }
 
 // Logf logs the provided message with formatting if the Logger is enabled.
 func Logf(logger *Logger, format string, args ...interface{}) {
-	logger.performLog(fmt.Sprintf(format, args...), false)
+	logger.performLazyLog(lazySprintf(format, args), false, nil)
 }
 
 // Logln logs the provided message followed by a new line if the Logger is enabled.
 func Logln(logger *Logger, msg ...interface{}) {
-	logger.performLog(fmt.Sprint(msg...), true)
+	logger.performLazyLog(lazySprint(msg), true, nil)
 }
 
 // Count returns the number of loggers that have been created.
 func Count() int {
+	loggersMu.RLock()
+	defer loggersMu.RUnlock()
 	return len(loggers)
 }
+
+// Loggers returns every Logger which has been created or added, ordered by ID.
+func Loggers() []*Logger {
+	return registeredLoggers()
+}
+
+// registeredLoggers returns all loggers which have been created, ordered by ID.
+func registeredLoggers() []*Logger {
+	loggersMu.RLock()
+	list := make([]*Logger, 0, len(loggers))
+	for l := range loggers {
+		list = append(list, l)
+	}
+	loggersMu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].id < list[j].id
+	})
+	return list
+}
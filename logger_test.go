@@ -0,0 +1,11 @@
+package logger
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	StartPoller()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,141 @@
+// Package loggrpc provides gRPC client and server interceptors which log every call through the logger package, in the
+// same way as the logger package's HTTPMiddleware does for HTTP requests:
+//
+//	interceptor := loggrpc.New(Incoming)
+//	server := grpc.NewServer(
+//		grpc.UnaryInterceptor(interceptor.UnaryServer()),
+//		grpc.StreamInterceptor(interceptor.StreamServer()),
+//	)
+//
+// Each call is logged with its full method name as the message, followed by the status code, the peer address and the
+// duration of the call, i.e. "/pkg.Service/Method code=OK peer=10.0.0.1:5000 duration=1.234ms".
+package loggrpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jemgunay/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Interceptor logs gRPC calls to its loggers.
+type Interceptor struct {
+	// Logger logs calls which complete successfully.
+	Logger *logger.Logger
+	// ErrorLogger logs calls which fail. It defaults to Logger.
+	ErrorLogger *logger.Logger
+	// ExcludeMethods are full method names which are not logged, i.e. "/grpc.health.v1.Health/Check".
+	ExcludeMethods []string
+}
+
+// New creates an Interceptor which logs every call to l, excluding the standard health checking service.
+func New(l *logger.Logger) *Interceptor {
+	return &Interceptor{
+		Logger:         l,
+		ExcludeMethods: []string{"/grpc.health.v1.Health/Check", "/grpc.health.v1.Health/Watch"},
+	}
+}
+
+// UnaryServer returns a server interceptor which logs unary calls.
+func (i *Interceptor) UnaryServer() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		i.log(ctx, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamServer returns a server interceptor which logs streaming calls once they have finished.
+func (i *Interceptor) StreamServer() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		i.log(ss.Context(), info.FullMethod, start, err)
+		return err
+	}
+}
+
+// UnaryClient returns a client interceptor which logs unary calls.
+func (i *Interceptor) UnaryClient() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var p peer.Peer
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(&p))...)
+		i.log(peer.NewContext(ctx, &p), method, start, err)
+		return err
+	}
+}
+
+// StreamClient returns a client interceptor which logs streaming calls once the stream has finished, i.e. when RecvMsg
+// returns an error or io.EOF.
+func (i *Interceptor) StreamClient() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		p := &peer.Peer{}
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, append(opts, grpc.Peer(p))...)
+		if err != nil {
+			i.log(peer.NewContext(ctx, p), method, start, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, finish: func(err error) {
+			i.log(peer.NewContext(ctx, p), method, start, err)
+		}}, nil
+	}
+}
+
+// log logs a completed call.
+func (i *Interceptor) log(ctx context.Context, method string, start time.Time, err error) {
+	for _, excluded := range i.ExcludeMethods {
+		if method == excluded {
+			return
+		}
+	}
+
+	l := i.Logger
+	if err != nil && i.ErrorLogger != nil {
+		l = i.ErrorLogger
+	}
+	if l == nil || !l.IsEnabled() {
+		return
+	}
+
+	code := status.Code(err)
+	fields := []logger.Field{logger.String("code", code.String())}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, logger.String("peer", p.Addr.String()))
+	}
+	fields = append(fields, logger.Duration("duration", time.Since(start)))
+	if err != nil && code != codes.OK {
+		fields = append(fields, logger.String(logger.ErrorKey, status.Convert(err).Message()))
+	}
+	l.LogFieldsContext(ctx, method, fields...)
+}
+
+// clientStream calls finish once the stream has finished.
+type clientStream struct {
+	grpc.ClientStream
+	once   sync.Once
+	finish func(err error)
+}
+
+// RecvMsg receives a message, finishing the stream once it returns an error. io.EOF marks a successful call.
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if err == io.EOF {
+				s.finish(nil)
+				return
+			}
+			s.finish(err)
+		})
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+// Package logprom exposes the logger package's metrics through the Prometheus client library. Collector is a
+// prometheus.Collector, so it can be registered alongside an application's own metrics:
+//
+//	prometheus.MustRegister(logprom.NewCollector(""))
+//
+// The metrics are the same as those served by logger.PrometheusCollector, which does not depend on the client library.
+package logprom
+
+import (
+	"strconv"
+
+	"github.com/jemgunay/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a prometheus.Collector which collects the statistics of all registered loggers, the logging queues and
+// the histograms of how long writes of each Category's entries take.
+type Collector struct {
+	messages      *prometheus.Desc
+	dropped       *prometheus.Desc
+	enabled       *prometheus.Desc
+	queueDepth    *prometheus.Desc
+	queueCapacity *prometheus.Desc
+	queueFull     *prometheus.Desc
+	writeDuration *prometheus.Desc
+}
+
+// NewCollector creates a Collector whose metric names are prefixed with namespace (default of "logger").
+func NewCollector(namespace string) *Collector {
+	if namespace == "" {
+		namespace = "logger"
+	}
+	loggerLabels := []string{"id", "category"}
+	return &Collector{
+		messages: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "messages_total"),
+			"Number of messages logged by each Logger.", loggerLabels, nil),
+		dropped: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "dropped_messages_total"),
+			"Number of messages discarded by each Logger before being written.", loggerLabels, nil),
+		enabled: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "enabled"),
+			"Whether each Logger is enabled.", loggerLabels, nil),
+		queueDepth: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "queue_depth"),
+			"Number of messages waiting in the buffered queue.", nil, nil),
+		queueCapacity: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "queue_capacity"),
+			"Size of the buffered queue.", nil, nil),
+		queueFull: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "queue_full_total"),
+			"Number of messages which found the queue full.", nil, nil),
+		writeDuration: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "write_duration_seconds"),
+			"Time taken to write each message to its Writer.", []string{"category"}, nil),
+	}
+}
+
+// Describe sends the descriptors of the metrics collected by the Collector to ch.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.messages
+	ch <- c.dropped
+	ch <- c.enabled
+	ch <- c.queueDepth
+	ch <- c.queueCapacity
+	ch <- c.queueFull
+	ch <- c.writeDuration
+}
+
+// Collect sends the current value of every metric to ch.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	stats := logger.Stats()
+	for _, l := range stats.Loggers {
+		id := strconv.Itoa(l.ID)
+		enabled := 0.0
+		if l.Enabled {
+			enabled = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.messages, prometheus.CounterValue, float64(l.Count), id, l.Category)
+		ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(l.Dropped), id, l.Category)
+		ch <- prometheus.MustNewConstMetric(c.enabled, prometheus.GaugeValue, enabled, id, l.Category)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.queueDepth, prometheus.GaugeValue, float64(stats.Queue.Depth))
+	ch <- prometheus.MustNewConstMetric(c.queueCapacity, prometheus.GaugeValue, float64(stats.Queue.Capacity))
+	ch <- prometheus.MustNewConstMetric(c.queueFull, prometheus.CounterValue, float64(stats.Queue.Full))
+
+	for category, h := range logger.WriteDurations() {
+		ch <- prometheus.MustNewConstHistogram(c.writeDuration, h.Count, h.Sum.Seconds(), h.Buckets, category)
+	}
+}
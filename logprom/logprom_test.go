@@ -0,0 +1,48 @@
+package logprom
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jemgunay/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	logger.StartPoller()
+
+	l := logger.NewLogger(&bytes.Buffer{}, "PROM", true)
+	defer logger.RemoveLogger(l)
+	l.Log("collected")
+	logger.Flush(time.Second)
+
+	c := NewCollector("")
+	if problems, err := testutil.CollectAndLint(c); err != nil || len(problems) > 0 {
+		t.Fatalf("collector failed lint: %v %v", err, problems)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make(map[string]bool)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "category" && label.GetValue() == "PROM" {
+					found[family.GetName()] = true
+				}
+			}
+		}
+	}
+	for _, name := range []string{"logger_messages_total", "logger_enabled", "logger_write_duration_seconds"} {
+		if !found[name] {
+			t.Errorf("expected %s to be collected for the PROM category", name)
+		}
+	}
+}
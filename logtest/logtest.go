@@ -0,0 +1,147 @@
+// Package logtest provides a Sink which captures logged entries in memory, so that unit tests can assert on what was
+// logged without parsing the text written to Stdout:
+//
+//	sink := logtest.Capture(Error)
+//	doSomething()
+//	if !sink.ContainsMessage("connection refused") {
+//		t.Error("expected a connection error to be logged")
+//	}
+package logtest
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jemgunay/logger"
+)
+
+// Sink is a logger.Sink which records every entry written to it.
+type Sink struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	entries []logger.Entry
+}
+
+// NewSink creates an empty Sink.
+func NewSink() *Sink {
+	s := &Sink{}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// Capture creates a Sink and sets it as the Writer of each of the provided Loggers.
+func Capture(loggers ...*logger.Logger) *Sink {
+	s := NewSink()
+	for _, l := range loggers {
+		l.Writer = s
+	}
+	return s
+}
+
+// WriteEntry records the entry.
+func (s *Sink) WriteEntry(e logger.Entry) error {
+	s.mu.Lock()
+	s.entries = append(s.entries, e)
+	s.mu.Unlock()
+	s.cond.Broadcast()
+	return nil
+}
+
+// Write implements io.Writer so that a Sink can be used as a Logger's Writer. Raw writes are recorded as LevelInfo
+// entries without a category.
+func (s *Sink) Write(p []byte) (int, error) {
+	message := bytes.TrimSpace(p)
+	if len(message) == 0 {
+		return len(p), nil
+	}
+	s.WriteEntry(logger.Entry{
+		Time:    time.Now(),
+		Level:   logger.LevelInfo,
+		Message: string(message),
+	})
+	return len(p), nil
+}
+
+// Entries returns a copy of the recorded entries in the order they were written.
+func (s *Sink) Entries() []logger.Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]logger.Entry(nil), s.entries...)
+}
+
+// Messages returns the Message of each recorded entry in the order they were written.
+func (s *Sink) Messages() []string {
+	entries := s.Entries()
+	messages := make([]string, len(entries))
+	for i, e := range entries {
+		messages[i] = e.Message
+	}
+	return messages
+}
+
+// ContainsMessage reports whether any recorded entry's Message contains substr.
+func (s *Sink) ContainsMessage(substr string) bool {
+	for _, e := range s.Entries() {
+		if strings.Contains(e.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CountByCategory returns the number of recorded entries with the provided Category Name.
+func (s *Sink) CountByCategory(category string) int {
+	count := 0
+	for _, e := range s.Entries() {
+		if e.Category == category {
+			count++
+		}
+	}
+	return count
+}
+
+// CountByLevel returns the number of recorded entries with the provided Level.
+func (s *Sink) CountByLevel(lvl logger.Level) int {
+	count := 0
+	for _, e := range s.Entries() {
+		if e.Level == lvl {
+			count++
+		}
+	}
+	return count
+}
+
+// Len returns the number of recorded entries.
+func (s *Sink) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Wait blocks until at least n entries have been recorded or the timeout elapses, reporting whether n entries were
+// recorded. Entries are written by the logger package's poller after the Logx call returns, so tests should Wait
+// before asserting.
+func (s *Sink) Wait(n int, timeout time.Duration) bool {
+	timer := time.AfterFunc(timeout, s.cond.Broadcast)
+	defer timer.Stop()
+	deadline := time.Now().Add(timeout)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.entries) < n {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		s.cond.Wait()
+	}
+	return true
+}
+
+// Reset discards all recorded entries.
+func (s *Sink) Reset() {
+	s.mu.Lock()
+	s.entries = nil
+	s.mu.Unlock()
+}
@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// defaultMappedChunkSize is the size of the region MappedFile preallocates and maps at a time if no size is provided.
+const defaultMappedChunkSize = 16 * 1024 * 1024
+
+// MappedFile is a Writer which appends to a file through a large preallocated, page-aligned window, for hot paths where
+// write latency matters more than durability. Each write is a memory copy rather than a syscall: where supported, the
+// window is memory-mapped, so the kernel writes it back to the file in the background, otherwise it is a buffer which
+// is written to the file once full or when Flush is called.
+//
+// The trade-off is the durability window. Mapped writes survive the process crashing but not the machine crashing
+// before the kernel has written them back, unless Sync is called. Buffered writes are lost if the process crashes
+// before they are flushed. The file is preallocated a window at a time, so it is padded with zero bytes until it is
+// closed; padding left behind by an unclean shutdown is trimmed when the file is next opened.
+type MappedFile struct {
+	mu        sync.Mutex
+	path      string
+	chunkSize int
+	useMmap   bool
+	file      *os.File
+
+	// window covers the file from offset, which is page-aligned. size is the length of the file's content.
+	window  []byte
+	offset  int64
+	size    int64
+	flushed int64
+}
+
+// OpenMappedFile opens (or creates) the file at path for appending through a preallocated window of chunkSize bytes
+// (default of 16MB), rounded up to a multiple of the page size. If useMmap is false, or memory-mapping is not supported
+// on this platform, the window is a buffer instead.
+func OpenMappedFile(path string, chunkSize int, useMmap bool) (*MappedFile, error) {
+	if chunkSize <= 0 {
+		chunkSize = defaultMappedChunkSize
+	}
+	pageSize := os.Getpagesize()
+	chunkSize = (chunkSize + pageSize - 1) / pageSize * pageSize
+
+	m := &MappedFile{
+		path:      path,
+		chunkSize: chunkSize,
+		useMmap:   useMmap && mmapSupported,
+	}
+	if err := m.open(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// open opens the file and maps the window containing its end. Zero padding left by an unclean shutdown is trimmed.
+func (m *MappedFile) open() error {
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	size, err := contentSize(file, m.chunkSize)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return err
+	}
+
+	m.file = file
+	m.size = size
+	m.flushed = size
+	if err := m.mapWindow(size - size%int64(os.Getpagesize())); err != nil {
+		file.Close()
+		return err
+	}
+	return nil
+}
+
+// contentSize returns the size of a file without any trailing zero padding, which is at most chunkSize long.
+func contentSize(f *os.File, chunkSize int) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	tail := int64(chunkSize)
+	if tail > size {
+		tail = size
+	}
+	buf := make([]byte, tail)
+	if _, err := f.ReadAt(buf, size-tail); err != nil && err != io.EOF {
+		return 0, err
+	}
+	trimmed := bytes.TrimRight(buf, "\x00")
+	return size - tail + int64(len(trimmed)), nil
+}
+
+// mapWindow preallocates and maps the window starting at the page-aligned offset.
+func (m *MappedFile) mapWindow(offset int64) error {
+	m.offset = offset
+	if err := m.file.Truncate(offset + int64(m.chunkSize)); err != nil {
+		return err
+	}
+	if !m.useMmap {
+		if m.window == nil {
+			m.window = make([]byte, m.chunkSize)
+		}
+		// the buffer must hold the content already in the file which precedes size within the window
+		if _, err := m.file.ReadAt(m.window[:m.size-offset], offset); err != nil && err != io.EOF {
+			return err
+		}
+		return nil
+	}
+
+	window, err := mmapFile(m.file, offset, m.chunkSize)
+	if err != nil {
+		return err
+	}
+	m.window = window
+	return nil
+}
+
+// Write copies p into the window, moving the window on through the file as each one fills.
+func (m *MappedFile) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	written := 0
+	for written < len(p) {
+		pos := int(m.size - m.offset)
+		if pos == len(m.window) {
+			if err := m.advance(); err != nil {
+				return written, err
+			}
+			pos = 0
+		}
+		n := copy(m.window[pos:], p[written:])
+		m.size += int64(n)
+		written += n
+	}
+	return written, nil
+}
+
+// advance moves the window on to the end of the current one, once it is full.
+func (m *MappedFile) advance() error {
+	if err := m.flushLocked(); err != nil {
+		return err
+	}
+	next := m.offset + int64(len(m.window))
+	if m.useMmap {
+		if err := munmapFile(m.window); err != nil {
+			return err
+		}
+		m.window = nil
+	}
+	return m.mapWindow(next)
+}
+
+// Flush writes any buffered content to the file. Mapped content is already part of the file, so is left for the kernel
+// to write back.
+func (m *MappedFile) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.flushLocked()
+}
+
+func (m *MappedFile) flushLocked() error {
+	if m.useMmap || m.flushed == m.size {
+		return nil
+	}
+	start := m.flushed - m.offset
+	if _, err := m.file.WriteAt(m.window[start:m.size-m.offset], m.flushed); err != nil {
+		return err
+	}
+	m.flushed = m.size
+	return nil
+}
+
+// Sync flushes any buffered content and commits the file to stable storage, closing the durability window.
+func (m *MappedFile) Sync() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.flushLocked(); err != nil {
+		return err
+	}
+	return m.file.Sync()
+}
+
+// Reopen closes the file and opens its path again, so that writing continues to a new file once the old one has been
+// moved aside by log rotation.
+func (m *MappedFile) Reopen() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.closeLocked(); err != nil {
+		return err
+	}
+	return m.open()
+}
+
+// Close flushes any buffered content, removes the preallocated padding and closes the file.
+func (m *MappedFile) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closeLocked()
+}
+
+func (m *MappedFile) closeLocked() error {
+	if m.file == nil {
+		return os.ErrClosed
+	}
+	err := m.flushLocked()
+	if m.useMmap && m.window != nil {
+		if unmapErr := munmapFile(m.window); err == nil {
+			err = unmapErr
+		}
+		m.window = nil
+	}
+	if truncateErr := m.file.Truncate(m.size); err == nil {
+		err = truncateErr
+	}
+	if closeErr := m.file.Close(); err == nil {
+		err = closeErr
+	}
+	m.file = nil
+	return err
+}
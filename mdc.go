@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// mdcKey is the context key under which WithFields stores Fields.
+type mdcKey struct{}
+
+// scope is the stack of Fields pushed by a goroutine.
+type scope struct {
+	fields []Field
+	// pushes is the number of Fields added by each push, so that PopFields removes exactly the Fields pushed with it.
+	pushes []int
+}
+
+var (
+	// scopes holds the Fields pushed by each goroutine, keyed by goroutine ID. scopeCount is the number of goroutines
+	// with pushed Fields, so that goroutine IDs are only looked up while Fields have been pushed.
+	scopes     = make(map[uint64]*scope)
+	scopesMu   sync.RWMutex
+	scopeCount int64
+)
+
+// WithFields returns a copy of ctx carrying fields in addition to any Fields already carried by ctx. The Fields are
+// attached to every entry logged with the context by the LogxContext functions, i.e. to attach a request ID to every
+// entry logged while handling a request.
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	existing, _ := ctx.Value(mdcKey{}).([]Field)
+	combined := make([]Field, 0, len(existing)+len(fields))
+	combined = append(append(combined, existing...), fields...)
+	return context.WithValue(ctx, mdcKey{}, combined)
+}
+
+// FieldsFromContext returns the Fields carried by ctx, which were added with WithFields.
+func FieldsFromContext(ctx context.Context) []Field {
+	fields, _ := ctx.Value(mdcKey{}).([]Field)
+	return fields
+}
+
+// PushFields attaches fields to every entry logged by the calling goroutine until they are removed with PopFields, in
+// the manner of a mapped diagnostic context. Pushes nest, so each PushFields should be paired with a deferred PopFields:
+//
+//	logger.PushFields(logger.String("job", job.ID))
+//	defer logger.PopFields()
+func PushFields(fields ...Field) {
+	id := goroutineID()
+	scopesMu.Lock()
+	defer scopesMu.Unlock()
+	sc, ok := scopes[id]
+	if !ok {
+		sc = &scope{}
+		scopes[id] = sc
+		atomic.AddInt64(&scopeCount, 1)
+	}
+	// copy rather than append in place, as scopeFields may have handed out the current slice
+	sc.fields = append(sc.fields[:len(sc.fields):len(sc.fields)], fields...)
+	sc.pushes = append(sc.pushes, len(fields))
+}
+
+// PopFields removes the Fields most recently pushed by the calling goroutine with PushFields.
+func PopFields() {
+	id := goroutineID()
+	scopesMu.Lock()
+	defer scopesMu.Unlock()
+	sc, ok := scopes[id]
+	if !ok {
+		return
+	}
+	n := sc.pushes[len(sc.pushes)-1]
+	sc.pushes = sc.pushes[:len(sc.pushes)-1]
+	sc.fields = sc.fields[:len(sc.fields)-n]
+	if len(sc.pushes) == 0 {
+		delete(scopes, id)
+		atomic.AddInt64(&scopeCount, -1)
+	}
+}
+
+// scopeFields returns the Fields pushed by the calling goroutine. The returned slice must not be modified.
+func scopeFields() []Field {
+	if atomic.LoadInt64(&scopeCount) == 0 {
+		return nil
+	}
+	id := goroutineID()
+	scopesMu.RLock()
+	defer scopesMu.RUnlock()
+	if sc, ok := scopes[id]; ok {
+		return sc.fields
+	}
+	return nil
+}
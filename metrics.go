@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// writeDurationBuckets are the upper bounds (in seconds) of the write latency histogram buckets.
+var writeDurationBuckets = []float64{0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1}
+
+// writeHistogram is a histogram of how long writes take.
+type writeHistogram struct {
+	buckets [12]uint64 // one per bucket, plus +Inf
+	sumNano uint64
+}
+
+// writeDurations holds a *writeHistogram of how long each write to a Logger's Writer takes for every Category Name,
+// recorded by the Writer goroutines.
+var writeDurations sync.Map
+
+// observeWriteDuration records the duration of a write of an entry of the category which started at the provided time.
+func observeWriteDuration(category string, start time.Time) {
+	d := time.Since(start)
+	seconds := d.Seconds()
+
+	i := 0
+	for i < len(writeDurationBuckets) && seconds > writeDurationBuckets[i] {
+		i++
+	}
+	h, ok := writeDurations.Load(category)
+	if !ok {
+		h, _ = writeDurations.LoadOrStore(category, &writeHistogram{})
+	}
+	atomic.AddUint64(&h.(*writeHistogram).buckets[i], 1)
+	atomic.AddUint64(&h.(*writeHistogram).sumNano, uint64(d))
+}
+
+// WriteDurationHistogram is a snapshot of the histogram of how long writes of a Category's entries took.
+type WriteDurationHistogram struct {
+	// Buckets maps the upper bound of each bucket in seconds to the number of writes which took at most that long.
+	Buckets map[float64]uint64
+	// Count is the total number of writes, including those which took longer than the largest bucket.
+	Count uint64
+	Sum   time.Duration
+}
+
+// WriteDurations returns a snapshot of the histograms of how long each write to a Logger's Writer took, keyed by the
+// Category Name of the entries written. Each Writer of a MultiSink is timed separately.
+func WriteDurations() map[string]WriteDurationHistogram {
+	histograms := make(map[string]WriteDurationHistogram)
+	writeDurations.Range(func(category, h interface{}) bool {
+		histograms[category.(string)] = h.(*writeHistogram).snapshot()
+		return true
+	})
+	return histograms
+}
+
+// snapshot returns the current counts of the histogram, with each bucket counting the writes of all smaller buckets.
+func (h *writeHistogram) snapshot() WriteDurationHistogram {
+	s := WriteDurationHistogram{
+		Buckets: make(map[float64]uint64, len(writeDurationBuckets)),
+		Sum:     time.Duration(atomic.LoadUint64(&h.sumNano)),
+	}
+	for i, bound := range writeDurationBuckets {
+		s.Count += atomic.LoadUint64(&h.buckets[i])
+		s.Buckets[bound] = s.Count
+	}
+	s.Count += atomic.LoadUint64(&h.buckets[len(writeDurationBuckets)])
+	return s
+}
+
+// QueueDepth returns the number of messages waiting in the buffered queues and the writer queues to be written.
+func QueueDepth() int {
+	return logQueue.len() + len(logQueuePriority) + writerQueueDepth()
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HTTPMiddleware logs a message for every request served by the handlers it wraps, in the form "GET /path" followed by
+// the selected Fields, i.e. "GET /users status=200 size=512 latency=1.234ms".
+type HTTPMiddleware struct {
+	Logger *Logger
+	// Fields selects the request and response details attached to each message, in order, from "status", "size",
+	// "latency", "remote_addr", "user_agent", "referer", "query" and "proto". It defaults to status, size and latency.
+	Fields []string
+	// ExcludePaths are request paths which are not logged, i.e. health checks. It defaults to "/healthz".
+	ExcludePaths []string
+}
+
+// NewHTTPMiddleware creates an HTTPMiddleware which logs requests to the provided Logger, i.e. an INCOMING Logger:
+//
+//	http.ListenAndServe(":8080", logger.NewHTTPMiddleware(Incoming).Handler(mux))
+func NewHTTPMiddleware(l *Logger) *HTTPMiddleware {
+	return &HTTPMiddleware{
+		Logger:       l,
+		Fields:       []string{"status", "size", "latency"},
+		ExcludePaths: []string{"/healthz"},
+	}
+}
+
+// Handler wraps next so that every request it serves is logged once it has been served. Trace correlation fields are
+// attached in the same way as LogContext.
+func (m *HTTPMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, path := range m.ExcludePaths {
+			if r.URL.Path == path {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		if !m.Logger.IsEnabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := now()
+		rw := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+		latency := now().Sub(start)
+
+		fields := contextFields(r.Context())
+		for _, name := range m.Fields {
+			switch strings.ToLower(name) {
+			case "status":
+				fields = append(fields, Int("status", rw.status))
+			case "size":
+				fields = append(fields, Int64("size", rw.size))
+			case "latency":
+				fields = append(fields, Duration("latency", latency))
+			case "remote_addr":
+				fields = append(fields, String("remote_addr", r.RemoteAddr))
+			case "user_agent":
+				fields = append(fields, String("user_agent", r.UserAgent()))
+			case "referer":
+				fields = append(fields, String("referer", r.Referer()))
+			case "query":
+				fields = append(fields, String("query", r.URL.RawQuery))
+			case "proto":
+				fields = append(fields, String("proto", r.Proto))
+			}
+		}
+		m.Logger.performLog(r.Method+" "+r.URL.Path, false, fields)
+	})
+}
+
+// responseRecorder records the status code and size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+// WriteHeader records the status code of the response.
+func (rw *responseRecorder) WriteHeader(status int) {
+	if !rw.wroteHeader {
+		rw.status = status
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the size of the response body.
+func (rw *responseRecorder) Write(p []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.ResponseWriter.Write(p)
+	rw.size += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher so that streaming responses, such as the web viewer's events, can be wrapped.
+func (rw *responseRecorder) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to access the underlying ResponseWriter.
+func (rw *responseRecorder) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
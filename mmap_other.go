@@ -0,0 +1,20 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package logger
+
+import (
+	"errors"
+	"os"
+)
+
+// mmapSupported reports whether MappedFile can memory-map files on this platform. It cannot, so MappedFile buffers
+// writes in memory instead.
+const mmapSupported = false
+
+func mmapFile(f *os.File, offset int64, length int) ([]byte, error) {
+	return nil, errors.New("mmap is not supported on this platform")
+}
+
+func munmapFile(b []byte) error {
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapSupported reports whether MappedFile can memory-map files on this platform.
+const mmapSupported = true
+
+// mmapFile maps length bytes of f, starting at the page-aligned offset, for writing.
+func mmapFile(f *os.File, offset int64, length int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), offset, length, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+// munmapFile unmaps a region mapped by mmapFile.
+func munmapFile(b []byte) error {
+	return syscall.Munmap(b)
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// MultiSink is a Writer which duplicates each message written by a Logger to several Writers, i.e. so that a Logger can
+// write to both Stdout and a file. Each Writer is written to independently: Sinks receive the structured Entry while
+// other Writers receive the composed text, and a failing Writer does not prevent the others from being written to. When
+// written by a Logger, each Writer is written from its own queue, so a slow Writer does not delay the others either.
+// Writers added with AddEncoded can also be given their own encoding and minimum Level.
+type MultiSink struct {
+	mu      sync.RWMutex
+	targets []multiTarget
+}
+
+// multiTarget is a single Writer of a MultiSink.
+type multiTarget struct {
+	writer   io.Writer
+	encoder  EncoderFunc
+	minLevel Level
+}
+
+// MultiWriter creates a MultiSink which writes every entry to each of the provided Writers in order.
+func MultiWriter(writers ...io.Writer) *MultiSink {
+	m := &MultiSink{}
+	m.Add(writers...)
+	return m
+}
+
+// Add appends Writers to the MultiSink which receive every entry in the Logger's own format.
+func (m *MultiSink) Add(writers ...io.Writer) {
+	m.mu.Lock()
+	for _, w := range writers {
+		m.targets = append(m.targets, multiTarget{writer: w, minLevel: LevelDebug})
+	}
+	m.mu.Unlock()
+}
+
+// AddLevel appends a Writer to the MultiSink which only receives entries with a Level of minLevel or above, in the
+// Logger's own format. Sharing a MultiSink between Loggers allows, for example, Warning and above to be written to
+// Stderr while every Level is written to a file.
+func (m *MultiSink) AddLevel(w io.Writer, minLevel Level) {
+	m.AddEncoded(w, nil, minLevel)
+}
+
+// AddEncoded appends a Writer to the MultiSink which only receives entries with a Level of minLevel or above, encoded by
+// encoder, i.e. AddEncoded(jsonFile, JSONEncoder, LevelWarning). A nil encoder writes entries in the Logger's own
+// format.
+func (m *MultiSink) AddEncoded(w io.Writer, encoder EncoderFunc, minLevel Level) {
+	m.mu.Lock()
+	m.targets = append(m.targets, multiTarget{writer: w, encoder: encoder, minLevel: minLevel})
+	m.mu.Unlock()
+}
+
+// Write implements io.Writer, writing p to every Writer regardless of its encoding or minimum Level. Every Writer is
+// attempted, and any errors are combined into the returned error.
+func (m *MultiSink) Write(p []byte) (int, error) {
+	err := m.each(func(t multiTarget) error {
+		_, err := t.writer.Write(p)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeEntry passes each Writer whose minimum Level the entry meets, along with its encoder, to write.
+func (m *MultiSink) writeEntry(e Entry, write func(w io.Writer, encoder EncoderFunc) error) error {
+	return m.each(func(t multiTarget) error {
+		if e.Level < t.minLevel {
+			return nil
+		}
+		return write(t.writer, t.encoder)
+	})
+}
+
+// each calls write for every Writer, continuing past failures. Any errors are combined into the returned error.
+func (m *MultiSink) each(write func(t multiTarget) error) error {
+	m.mu.RLock()
+	targets := m.targets
+	m.mu.RUnlock()
+
+	var errs multiError
+	for _, t := range targets {
+		if err := write(t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// multiError combines the errors returned by several Writers.
+type multiError []error
+
+func (e multiError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,39 @@
+package logger
+
+import "time"
+
+// LogOnce logs the provided message the first time it is called with key, and ignores any later calls with the same
+// key, i.e. for deprecation warnings or conditions which are detected repeatedly. Calls made while the Logger is
+// disabled do not use up the key.
+func (l *Logger) LogOnce(key string, msg ...interface{}) {
+	l.LogOncePer(key, 0, msg...)
+}
+
+// LogOncePer logs the provided message at most once per interval for each key. An interval of 0 or less logs the
+// message only once, as with LogOnce.
+func (l *Logger) LogOncePer(key string, interval time.Duration, msg ...interface{}) {
+	if !l.IsEnabled() {
+		return
+	}
+
+	current := now()
+	if interval <= 0 {
+		if _, loaded := l.onceKeys.LoadOrStore(key, current); loaded {
+			return
+		}
+	} else {
+		for {
+			last, loaded := l.onceKeys.LoadOrStore(key, current)
+			if !loaded {
+				break
+			}
+			if current.Sub(last.(time.Time)) < interval {
+				return
+			}
+			if l.onceKeys.CompareAndSwap(key, last, current) {
+				break
+			}
+		}
+	}
+	l.performLazyLog(lazySprint(msg), false, nil)
+}
@@ -0,0 +1,26 @@
+// Package oteltrace correlates logged entries with OpenTelemetry traces. Importing the package sets the logger
+// package's TraceExtractor, so entries logged via the LogxContext functions with a context carrying an active span have
+// trace_id and span_id fields attached:
+//
+//	import _ "github.com/jemgunay/logger/oteltrace"
+package oteltrace
+
+import (
+	"context"
+
+	"github.com/jemgunay/logger"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	logger.SetTraceExtractor(Extract)
+}
+
+// Extract returns the hex encoded trace and span IDs of the OpenTelemetry span carried by ctx, if it is valid.
+func Extract(ctx context.Context) (traceID, spanID string, ok bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", "", false
+	}
+	return sc.TraceID().String(), sc.SpanID().String(), true
+}
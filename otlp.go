@@ -0,0 +1,266 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OTLPProtocol is the transport used by an OTLPSink to export entries to an OpenTelemetry collector.
+type OTLPProtocol int
+
+const (
+	// OTLPHTTP exports protobuf encoded requests over HTTP, to the collector's /v1/logs endpoint (default port 4318).
+	OTLPHTTP OTLPProtocol = iota
+	// OTLPGRPC exports requests via the gRPC LogsService (default port 4317).
+	OTLPGRPC
+)
+
+const (
+	otlpHTTPPath  = "/v1/logs"
+	otlpGRPCPath  = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+	otlpScopeName = "github.com/jemgunay/logger"
+)
+
+// OTLPSink is a Sink which exports entries to an OpenTelemetry collector using the OTLP protocol. Each entry becomes an
+// OTel LogRecord: the Level is mapped to the record's severity, the Message becomes its body and the Category and Fields
+// are added as attributes, except for trace_id and span_id Fields which populate the record's trace context. Entries
+// are exported in batches in the same way as the DatadogSink. The zero value can be used once Endpoint has been set.
+type OTLPSink struct {
+	// Endpoint is the base URL of the collector, i.e. "http://localhost:4318" for OTLPHTTP or "http://localhost:4317"
+	// for OTLPGRPC. Plain http endpoints are sent unencrypted HTTP/2 (h2c) when using gRPC.
+	Endpoint string
+	Protocol OTLPProtocol
+	// Headers are added to every export request, i.e. for collector authentication.
+	Headers map[string]string
+	// ResourceAttributes describe the entity producing the logs. The "service.name" attribute defaults to the name of
+	// the executable.
+	ResourceAttributes map[string]string
+
+	BatchSize     int
+	FlushInterval time.Duration
+	// Client is the HTTP client used to export requests. A nil Client uses one configured for the Protocol, as created
+	// by NewOTLPSink.
+	Client *http.Client
+
+	batcherOnce sync.Once
+	batcher     *batcher
+	// client is used in place of a nil Client.
+	client *http.Client
+}
+
+// NewOTLPSink creates an OTLPSink which exports to the collector at the provided endpoint using the given protocol.
+// Close must be called to flush any remaining entries before the program terminates.
+func NewOTLPSink(endpoint string, protocol OTLPProtocol) *OTLPSink {
+	s := &OTLPSink{
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		Protocol: protocol,
+		ResourceAttributes: map[string]string{
+			"service.name": filepath.Base(os.Args[0]),
+		},
+		BatchSize:     512,
+		FlushInterval: defaultFlushInterval,
+		Client:        newOTLPClient(protocol),
+	}
+	s.batcher = newBatcher(s.export)
+	return s
+}
+
+// newOTLPClient creates an HTTP client for exporting with the protocol.
+func newOTLPClient(protocol OTLPProtocol) *http.Client {
+	// gRPC requires HTTP/2, including over unencrypted connections
+	var protocols http.Protocols
+	protocols.SetHTTP1(protocol == OTLPHTTP)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(protocol == OTLPGRPC)
+
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{Protocols: &protocols},
+	}
+}
+
+// queue returns the sink's batcher, creating it if the sink was not created with NewOTLPSink.
+func (s *OTLPSink) queue() *batcher {
+	s.batcherOnce.Do(func() {
+		if s.batcher == nil {
+			s.batcher = newBatcher(s.export)
+		}
+		s.client = newOTLPClient(s.Protocol)
+	})
+	return s.batcher
+}
+
+// WriteEntry queues the entry to be exported with the next batch. If a batch has failed to be exported since the
+// previous entry was written, its error is returned and the entry is not queued.
+func (s *OTLPSink) WriteEntry(e Entry) error {
+	return s.queue().add(e, s.BatchSize, s.FlushInterval)
+}
+
+// Write implements io.Writer so that an OTLPSink can be used as a Logger's Writer. Raw writes are exported as LevelInfo
+// entries without a category.
+func (s *OTLPSink) Write(p []byte) (int, error) {
+	message := strings.TrimSpace(string(p))
+	if message == "" {
+		return len(p), nil
+	}
+	if err := s.WriteEntry(Entry{Time: now(), Level: LevelInfo, Message: message}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush exports any queued entries immediately.
+func (s *OTLPSink) Flush() error {
+	return s.queue().flush()
+}
+
+// Close stops the background exporter once any queued entries have been exported, returning the error of the final
+// batch.
+func (s *OTLPSink) Close() error {
+	return s.queue().close()
+}
+
+// export sends a batch of entries to the collector as a single ExportLogsServiceRequest.
+func (s *OTLPSink) export(batch []Entry) error {
+	body := s.encodeRequest(batch)
+
+	client := s.Client
+	if client == nil {
+		client = s.client
+	}
+
+	var req *http.Request
+	var err error
+	switch s.Protocol {
+	case OTLPGRPC:
+		// length-prefixed gRPC message frame: uncompressed flag followed by the big endian message length
+		frame := make([]byte, 5, 5+len(body))
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(body)))
+		req, err = http.NewRequest(http.MethodPost, s.Endpoint+otlpGRPCPath, bytes.NewReader(append(frame, body...)))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/grpc")
+		req.Header.Set("TE", "trailers")
+	default:
+		req, err = http.NewRequest(http.MethodPost, s.Endpoint+otlpHTTPPath, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-protobuf")
+	}
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// the body must be read to completion for gRPC trailers to be populated
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("otlp: unexpected response status: %s", resp.Status)
+	}
+	if s.Protocol == OTLPGRPC {
+		status := resp.Trailer.Get("Grpc-Status")
+		if status == "" {
+			// trailers-only responses carry the status in the headers
+			status = resp.Header.Get("Grpc-Status")
+		}
+		if status != "0" {
+			msg := resp.Trailer.Get("Grpc-Message")
+			if msg == "" {
+				msg = resp.Header.Get("Grpc-Message")
+			}
+			return errors.New("otlp: grpc status " + status + ": " + msg)
+		}
+	}
+	return nil
+}
+
+// encodeRequest encodes an opentelemetry.proto.collector.logs.v1.ExportLogsServiceRequest containing the entries.
+func (s *OTLPSink) encodeRequest(batch []Entry) []byte {
+	var resource []byte
+	for k, v := range s.ResourceAttributes {
+		resource = protoAppendBytes(resource, 1, otlpKeyValue(k, v))
+	}
+
+	scopeLogs := protoAppendBytes(nil, 1, protoAppendString(nil, 1, otlpScopeName))
+	for _, e := range batch {
+		scopeLogs = protoAppendBytes(scopeLogs, 2, otlpLogRecord(e))
+	}
+
+	resourceLogs := protoAppendBytes(nil, 1, resource)
+	resourceLogs = protoAppendBytes(resourceLogs, 2, scopeLogs)
+
+	return protoAppendBytes(nil, 1, resourceLogs)
+}
+
+// otlpLogRecord encodes an entry as an opentelemetry.proto.logs.v1.LogRecord.
+func otlpLogRecord(e Entry) []byte {
+	var record []byte
+	record = protoAppendFixed64(record, 1, uint64(e.Time.UnixNano()))
+	record = protoAppendUint(record, 2, uint64(otlpSeverityNumber(e.Level)))
+	record = protoAppendString(record, 3, e.Level.String())
+	record = protoAppendBytes(record, 5, otlpStringValue(e.Message))
+	if e.Category != "" {
+		record = protoAppendBytes(record, 6, otlpKeyValue("category", e.Category))
+	}
+	for _, f := range e.Fields {
+		// trace correlation fields populate the record's own trace context rather than its attributes
+		switch f.Key {
+		case TraceIDKey:
+			if id, err := hex.DecodeString(f.String()); err == nil && len(id) == 16 {
+				record = protoAppendBytes(record, 9, id)
+				continue
+			}
+		case SpanIDKey:
+			if id, err := hex.DecodeString(f.String()); err == nil && len(id) == 8 {
+				record = protoAppendBytes(record, 10, id)
+				continue
+			}
+		}
+		record = protoAppendBytes(record, 6, otlpKeyValue(f.Key, f.String()))
+	}
+	record = protoAppendFixed64(record, 11, uint64(now().UnixNano()))
+	return record
+}
+
+// otlpKeyValue encodes an opentelemetry.proto.common.v1.KeyValue with a string value.
+func otlpKeyValue(key, value string) []byte {
+	kv := protoAppendString(nil, 1, key)
+	return protoAppendBytes(kv, 2, otlpStringValue(value))
+}
+
+// otlpStringValue encodes an opentelemetry.proto.common.v1.AnyValue holding a string.
+func otlpStringValue(s string) []byte {
+	return protoAppendString(nil, 1, s)
+}
+
+// otlpSeverityNumber maps a Level onto the base OTel SeverityNumber of the corresponding severity range.
+func otlpSeverityNumber(lvl Level) int {
+	switch {
+	case lvl >= LevelFatal:
+		return 21
+	case lvl >= LevelError:
+		return 17
+	case lvl >= LevelWarning:
+		return 13
+	case lvl >= LevelInfo:
+		return 9
+	}
+	return 5
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// protoFields decodes the length-delimited and fixed64 fields of a protobuf message, keyed by field number. Varint
+// fields are decoded into their encoded bytes.
+func protoFields(t *testing.T, b []byte) map[int][][]byte {
+	t.Helper()
+	fields := make(map[int][][]byte)
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatal("truncated tag")
+		}
+		b = b[n:]
+		field := int(tag >> 3)
+		switch tag & 7 {
+		case 0:
+			_, n = binary.Uvarint(b)
+			if n <= 0 {
+				t.Fatal("truncated varint")
+			}
+			fields[field] = append(fields[field], b[:n])
+			b = b[n:]
+		case 1:
+			fields[field] = append(fields[field], b[:8])
+			b = b[8:]
+		case 2:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				t.Fatal("truncated field")
+			}
+			fields[field] = append(fields[field], b[n:n+int(length)])
+			b = b[n+int(length):]
+		default:
+			t.Fatalf("unexpected wire type %d", tag&7)
+		}
+	}
+	return fields
+}
+
+func TestOTLPSinkExportsOverH2C(t *testing.T) {
+	requests := make(chan []byte, 1)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 || r.URL.Path != otlpGRPCPath || r.Header.Get("Content-Type") != "application/grpc" {
+			t.Errorf("unexpected request: %s %s %s", r.Proto, r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil || len(body) < 5 || int(binary.BigEndian.Uint32(body[1:5])) != len(body)-5 {
+			t.Errorf("invalid gRPC frame: %v", err)
+		}
+		requests <- body[5:]
+
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.Header().Set("Content-Type", "application/grpc")
+		w.WriteHeader(http.StatusOK)
+		w.Header().Set("Grpc-Status", "0")
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	// the zero value must speak h2c to a plain http endpoint
+	sink := &OTLPSink{Endpoint: server.URL, Protocol: OTLPGRPC}
+	traceID, spanID := "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	at := time.Unix(0, 1600000000123456789)
+	err := sink.WriteEntry(Entry{
+		Time:     at,
+		Level:    LevelError,
+		Category: "OTLP",
+		Message:  "exported",
+		Fields:   []Field{String(TraceIDKey, traceID), String(SpanIDKey, spanID), Int("attempt", 2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var request []byte
+	select {
+	case request = <-requests:
+	default:
+		t.Fatal("no request was exported")
+	}
+	resourceLogs := protoFields(t, request)[1][0]
+	scopeLogs := protoFields(t, resourceLogs)[2][0]
+	logRecords := protoFields(t, scopeLogs)[2]
+	if len(logRecords) != 1 {
+		t.Fatalf("expected 1 LogRecord, got %d", len(logRecords))
+	}
+	record := protoFields(t, logRecords[0])
+
+	if ts := binary.LittleEndian.Uint64(record[1][0]); ts != uint64(at.UnixNano()) {
+		t.Errorf("time_unix_nano is %d, expected %d", ts, at.UnixNano())
+	}
+	if severity, _ := binary.Uvarint(record[2][0]); severity != 17 {
+		t.Errorf("severity_number is %d, expected 17", severity)
+	}
+	if text := string(record[3][0]); text != "ERROR" {
+		t.Errorf("severity_text is %q, expected ERROR", text)
+	}
+	if body := string(protoFields(t, record[5][0])[1][0]); body != "exported" {
+		t.Errorf("body is %q, expected exported", body)
+	}
+	if id := hex.EncodeToString(record[9][0]); id != traceID {
+		t.Errorf("trace_id is %s, expected %s", id, traceID)
+	}
+	if id := hex.EncodeToString(record[10][0]); id != spanID {
+		t.Errorf("span_id is %s, expected %s", id, spanID)
+	}
+
+	attributes := make(map[string]string)
+	for _, kv := range record[6] {
+		fields := protoFields(t, kv)
+		attributes[string(fields[1][0])] = string(protoFields(t, fields[2][0])[1][0])
+	}
+	if len(attributes) != 2 || attributes["category"] != "OTLP" || attributes["attempt"] != "2" {
+		t.Errorf("unexpected attributes %v", attributes)
+	}
+}
+
+func TestOTLPSinkReportsFailedExports(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := NewOTLPSink(server.URL, OTLPHTTP)
+	if err := sink.WriteEntry(Entry{Message: "unavailable"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err == nil {
+		t.Fatal("expected Close to return the error of the final export")
+	}
+}
@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+// PagerDutyEventsURL is the PagerDuty Events API v2 endpoint which PagerDutySinks send events to by default.
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// pagerDutySummaryLimit is the maximum summary length accepted by the Events API.
+const pagerDutySummaryLimit = 1024
+
+// PagerDutySink is a Sink which triggers PagerDuty incidents via the Events API v2. Only entries with a Level of
+// MinLevel or above trigger an event. Each event is given a dedup key derived from the entry's category and message, so
+// repeatedly logging the same failure updates a single open incident rather than raising an alert storm.
+type PagerDutySink struct {
+	// RoutingKey is the integration key of the PagerDuty service to trigger incidents on.
+	RoutingKey string
+	// MinLevel is the minimum Level an entry must have to trigger an incident. NewPagerDutySink sets it to LevelError,
+	// but the zero value is LevelInfo, i.e. a PagerDutySink literal triggers incidents for every entry other than Debug.
+	MinLevel Level
+	// Source is the affected system reported in the event payload (default of the host name).
+	Source string
+	// URL is the Events API endpoint (default of PagerDutyEventsURL).
+	URL    string
+	Client *http.Client
+}
+
+// NewPagerDutySink creates a PagerDutySink which triggers incidents for LevelError and LevelFatal entries on the
+// service with the provided routing key.
+func NewPagerDutySink(routingKey string) *PagerDutySink {
+	source, _ := os.Hostname()
+	return &PagerDutySink{
+		RoutingKey: routingKey,
+		MinLevel:   LevelError,
+		Source:     source,
+		URL:        PagerDutyEventsURL,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// pagerDutyEvent is the Events API v2 request body.
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+type pagerDutyPayload struct {
+	Summary   string `json:"summary"`
+	Source    string `json:"source"`
+	Severity  string `json:"severity"`
+	Timestamp string `json:"timestamp"`
+	Component string `json:"component,omitempty"`
+}
+
+// WriteEntry triggers a PagerDuty incident for the entry if its Level is at least MinLevel.
+func (s *PagerDutySink) WriteEntry(e Entry) error {
+	if e.Level < s.MinLevel {
+		return nil
+	}
+
+	summary := e.Message
+	if e.Category != "" {
+		summary = "[" + e.Category + "] " + summary
+	}
+	if len(summary) > pagerDutySummaryLimit {
+		// cut at a rune boundary so that a multi-byte character is not split
+		cut := pagerDutySummaryLimit
+		for cut > 0 && !utf8.RuneStart(summary[cut]) {
+			cut--
+		}
+		summary = summary[:cut]
+	}
+
+	source := s.Source
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+
+	event := pagerDutyEvent{
+		RoutingKey:  s.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    PagerDutyDedupKey(e),
+		Payload: pagerDutyPayload{
+			Summary:   summary,
+			Source:    source,
+			Severity:  pagerDutySeverity(e.Level),
+			Timestamp: e.Time.Format(time.RFC3339Nano),
+			Component: e.Category,
+		},
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	url := s.URL
+	if url == "" {
+		url = PagerDutyEventsURL
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("pagerduty: unexpected response status: %s", resp.Status)
+	}
+	return nil
+}
+
+// Write implements io.Writer so that a PagerDutySink can be used as a Logger's Writer. Raw writes (which have no
+// structure) are sent as LevelError entries without a category.
+func (s *PagerDutySink) Write(p []byte) (int, error) {
+	message := bytes.TrimSpace(p)
+	if len(message) == 0 {
+		return len(p), nil
+	}
+	e := Entry{
+		Time:    now(),
+		Level:   LevelError,
+		Message: string(message),
+	}
+	if err := s.WriteEntry(e); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// PagerDutyDedupKey returns the dedup key used for an entry: a hex encoded SHA-256 hash of its category and message.
+func PagerDutyDedupKey(e Entry) string {
+	sum := sha256.Sum256([]byte(e.Category + "\x00" + e.Message))
+	return hex.EncodeToString(sum[:])
+}
+
+// pagerDutySeverity maps a Level onto one of the severities accepted by the Events API.
+func pagerDutySeverity(lvl Level) string {
+	switch {
+	case lvl >= LevelFatal:
+		return "critical"
+	case lvl >= LevelError:
+		return "error"
+	case lvl >= LevelWarning:
+		return "warning"
+	}
+	return "info"
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// pagerDutyServer records the events sent to it, responding with status.
+func pagerDutyServer(t *testing.T, status int) (*httptest.Server, chan pagerDutyEvent) {
+	t.Helper()
+	events := make(chan pagerDutyEvent, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event pagerDutyEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to decode event: %s", err)
+		}
+		events <- event
+		w.WriteHeader(status)
+	}))
+	return server, events
+}
+
+func TestPagerDutySinkTriggersEvents(t *testing.T) {
+	server, events := pagerDutyServer(t, http.StatusAccepted)
+	defer server.Close()
+
+	sink := NewPagerDutySink("routing-key")
+	sink.URL = server.URL
+	sink.Source = "host-1"
+
+	// entries below MinLevel are not sent
+	if err := sink.WriteEntry(Entry{Level: LevelWarning, Category: "DB", Message: "slow query"}); err != nil {
+		t.Fatal(err)
+	}
+	e := Entry{Level: LevelFatal, Category: "DB", Message: "connection lost"}
+	if err := sink.WriteEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	event := <-events
+	if event.RoutingKey != "routing-key" || event.EventAction != "trigger" || event.DedupKey != PagerDutyDedupKey(e) {
+		t.Errorf("unexpected event %+v", event)
+	}
+	expected := pagerDutyPayload{
+		Summary:   "[DB] connection lost",
+		Source:    "host-1",
+		Severity:  "critical",
+		Timestamp: event.Payload.Timestamp,
+		Component: "DB",
+	}
+	if event.Payload != expected {
+		t.Errorf("unexpected payload %+v, expected %+v", event.Payload, expected)
+	}
+
+	// the same category and message are deduplicated into the same incident, unlike a different message
+	if PagerDutyDedupKey(Entry{Category: "DB", Message: "connection lost", Level: LevelError}) != event.DedupKey {
+		t.Error("expected the dedup key to depend only on the category and message")
+	}
+	if PagerDutyDedupKey(Entry{Category: "DB", Message: "connection restored"}) == event.DedupKey {
+		t.Error("expected different messages to have different dedup keys")
+	}
+}
+
+func TestPagerDutySinkTruncatesSummary(t *testing.T) {
+	server, events := pagerDutyServer(t, http.StatusAccepted)
+	defer server.Close()
+
+	sink := NewPagerDutySink("routing-key")
+	sink.URL = server.URL
+	// a multi-byte character straddles the limit, so must be cut rather than split
+	message := strings.Repeat("a", pagerDutySummaryLimit-2) + "€"
+	if _, err := sink.Write([]byte(message + "\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	summary := (<-events).Payload.Summary
+	if len(summary) > pagerDutySummaryLimit || !utf8.ValidString(summary) {
+		t.Errorf("summary of %d bytes was not truncated at a rune boundary", len(summary))
+	}
+	if summary != message[:pagerDutySummaryLimit-2] {
+		t.Error("expected the summary to be cut before the multi-byte character")
+	}
+}
+
+func TestPagerDutySinkReportsRejectedEvents(t *testing.T) {
+	server, _ := pagerDutyServer(t, http.StatusBadRequest)
+	defer server.Close()
+
+	sink := NewPagerDutySink("routing-key")
+	sink.URL = server.URL
+	if err := sink.WriteEntry(Entry{Level: LevelError, Message: "rejected"}); err == nil {
+		t.Error("expected an error for a rejected event")
+	}
+}
+
+func TestPagerDutySinkZeroValue(t *testing.T) {
+	server, events := pagerDutyServer(t, http.StatusAccepted)
+	defer server.Close()
+
+	// a literal has a MinLevel of LevelInfo and reports the host name as its Source
+	sink := &PagerDutySink{RoutingKey: "routing-key", URL: server.URL}
+	if err := sink.WriteEntry(Entry{Level: LevelDebug, Message: "ignored"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.WriteEntry(Entry{Level: LevelInfo, Message: "triggered"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	host, _ := os.Hostname()
+	if event := <-events; event.Payload.Summary != "triggered" || event.Payload.Source != host {
+		t.Errorf("unexpected payload %+v", event.Payload)
+	}
+}
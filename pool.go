@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferSize is the largest buffer returned to bufferPool, so that a single very large message does not keep
+// its buffer allocated for the life of the program.
+const maxPooledBufferSize = 64 * 1024
+
+// bufferPool holds the buffers used to compose and write messages, so that steady-state logging reuses them rather than
+// allocating new ones for every message. Each line is composed into a pooled buffer by the goroutine of its Writer and
+// written from it directly. Entries themselves are not pooled, as they are passed to hooks, sinks and the viewer, which
+// may keep them after the message has been written.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from bufferPool.
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer resets a buffer and returns it to bufferPool. The buffer must not be used afterwards.
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBufferSize {
+		return
+	}
+	b.Reset()
+	bufferPool.Put(b)
+}
+
+// writeLine writes s followed by a newline to w in a single Write call, using a pooled buffer.
+func writeLine(w io.Writer, s string) error {
+	b := getBuffer()
+	defer putBuffer(b)
+	b.WriteString(s)
+	b.WriteByte('\n')
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,43 @@
+package logger
+
+var (
+	// logQueuePriority is the buffered queue of messages with a Level of priorityLevel or above.
+	logQueuePriority = make(chan queueItem, BufferSize)
+	priorityLevel    = LevelError
+)
+
+// SetPriorityLevel sets the minimum Level of messages which are written ahead of other messages, i.e. so that an ERROR
+// is written immediately rather than waiting behind a backlog of buffered INFO messages. Priority messages have their
+// own lane in the buffered queue and in each Writer's queue, so they are never held up or spilled to disk behind
+// messages of a lower Level. As a result, priority messages may be written before messages which were logged earlier;
+// the Sequence of each Entry records the order in which they were logged. The default is LevelError.
+func SetPriorityLevel(lvl Level) {
+	priorityLevel = lvl
+}
+
+// isPriority reports whether a message should use the priority lanes.
+func isPriority(item queueItem) bool {
+	return item.entry.Level >= priorityLevel
+}
+
+// enqueuePriority sends a priority message to the priority queue, reporting false if the queue is full.
+func enqueuePriority(item queueItem) bool {
+	select {
+	case logQueuePriority <- item:
+		return true
+	default:
+		return false
+	}
+}
+
+// dispatchPriority dispatches every message waiting in the priority queue.
+func dispatchPriority() {
+	for {
+		select {
+		case item := <-logQueuePriority:
+			dispatch(item)
+		default:
+			return
+		}
+	}
+}
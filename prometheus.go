@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusCollector exposes logging activity as Prometheus metrics in the text exposition format, so it can be
+// scraped without depending on the Prometheus client library. It is an http.Handler, typically served on /metrics:
+//
+//	http.Handle("/metrics", logger.NewPrometheusCollector())
+//
+// It is not a prometheus.Collector, so cannot be registered alongside an application's own metrics. The logprom
+// package provides a prometheus.Collector which exposes the same metrics.
+//
+// The following metrics are exposed:
+//
+//	logger_messages_total{id,category}          messages logged by each Logger
+//	logger_dropped_messages_total{id,category}  messages logged by each Logger which were discarded before being written
+//	logger_enabled{id,category}                 whether each Logger is enabled
+//	logger_queue_depth                          messages waiting in the buffered queue
+//	logger_queue_capacity                       size of the buffered queue
+//	logger_queue_full_total                     messages which found the buffered queue full
+//	logger_write_duration_seconds{category}     histogram of the time taken to write each message
+type PrometheusCollector struct {
+	// Namespace is prefixed to every metric name (default of "logger").
+	Namespace string
+}
+
+// NewPrometheusCollector creates a PrometheusCollector for all loggers.
+func NewPrometheusCollector() *PrometheusCollector {
+	return &PrometheusCollector{Namespace: "logger"}
+}
+
+// ServeHTTP writes the current metrics in the Prometheus text exposition format.
+func (c *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the current metrics in the Prometheus text exposition format to w.
+func (c *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	ns := c.Namespace
+	if ns == "" {
+		ns = "logger"
+	}
+
+	bw := &strings.Builder{}
+	list := registeredLoggers()
+
+	writeHeader := func(name, typ, help string) {
+		fmt.Fprintf(bw, "# HELP %s_%s %s\n# TYPE %s_%s %s\n", ns, name, help, ns, name, typ)
+	}
+	writeLoggerMetric := func(name string, value func(l *Logger) float64) {
+		for _, l := range list {
+			fmt.Fprintf(bw, "%s_%s{id=\"%d\",category=\"%s\"} %s\n", ns, name, l.id,
+				escapeLabel(l.Category.Name), formatFloat(value(l)))
+		}
+	}
+
+	writeHeader("messages_total", "counter", "Number of messages logged by each Logger.")
+	writeLoggerMetric("messages_total", func(l *Logger) float64 { return float64(l.Count()) })
+
+	writeHeader("dropped_messages_total", "counter", "Number of messages discarded by each Logger before being written.")
+	writeLoggerMetric("dropped_messages_total", func(l *Logger) float64 { return float64(l.Dropped()) })
+
+	writeHeader("enabled", "gauge", "Whether each Logger is enabled.")
+	writeLoggerMetric("enabled", func(l *Logger) float64 {
+		if l.IsEnabled() {
+			return 1
+		}
+		return 0
+	})
+
+	writeHeader("queue_depth", "gauge", "Number of messages waiting in the buffered queue.")
+	fmt.Fprintf(bw, "%s_queue_depth %d\n", ns, QueueDepth())
+	writeHeader("queue_capacity", "gauge", "Size of the buffered queue.")
+	fmt.Fprintf(bw, "%s_queue_capacity %d\n", ns, logQueue.cap())
+	writeHeader("queue_full_total", "counter", "Number of messages which found the queue full.")
+	fmt.Fprintf(bw, "%s_queue_full_total %d\n", ns, logQueue.fullCount())
+
+	writeHeader("write_duration_seconds", "histogram", "Time taken to write each message to its Writer.")
+	histograms := WriteDurations()
+	categories := make([]string, 0, len(histograms))
+	for category := range histograms {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		h, label := histograms[category], escapeLabel(category)
+		for _, bound := range writeDurationBuckets {
+			fmt.Fprintf(bw, "%s_write_duration_seconds_bucket{category=\"%s\",le=\"%s\"} %d\n", ns, label,
+				formatFloat(bound), h.Buckets[bound])
+		}
+		fmt.Fprintf(bw, "%s_write_duration_seconds_bucket{category=\"%s\",le=\"+Inf\"} %d\n", ns, label, h.Count)
+		fmt.Fprintf(bw, "%s_write_duration_seconds_sum{category=\"%s\"} %s\n", ns, label, formatFloat(h.Sum.Seconds()))
+		fmt.Fprintf(bw, "%s_write_duration_seconds_count{category=\"%s\"} %d\n", ns, label, h.Count)
+	}
+
+	n, err := io.WriteString(w, bw.String())
+	return int64(n), err
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
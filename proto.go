@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Protocol buffer wire types, used to hand encode the small number of messages the package sends without depending on
+// a protobuf library.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+)
+
+func protoAppendVarint(b []byte, v uint64) []byte {
+	return binary.AppendUvarint(b, v)
+}
+
+func protoAppendTag(b []byte, field, wireType int) []byte {
+	return protoAppendVarint(b, uint64(field)<<3|uint64(wireType))
+}
+
+// protoAppendUint appends a varint field. Zero values are omitted, as in proto3.
+func protoAppendUint(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protoAppendTag(b, field, protoVarint)
+	return protoAppendVarint(b, v)
+}
+
+// protoAppendBool appends a bool field. False values are omitted, as in proto3.
+func protoAppendBool(b []byte, field int, v bool) []byte {
+	if !v {
+		return b
+	}
+	return protoAppendUint(b, field, 1)
+}
+
+// protoAppendFixed64 appends a fixed64 field. Zero values are omitted, as in proto3.
+func protoAppendFixed64(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protoAppendTag(b, field, protoFixed64)
+	return binary.LittleEndian.AppendUint64(b, v)
+}
+
+// protoAppendDouble appends a double field. Zero values are omitted, as in proto3.
+func protoAppendDouble(b []byte, field int, v float64) []byte {
+	return protoAppendFixed64(b, field, math.Float64bits(v))
+}
+
+// protoAppendBytes appends a length-delimited field, which is used for strings, bytes and embedded messages. Empty
+// values are omitted, as in proto3.
+func protoAppendBytes(b []byte, field int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protoAppendTag(b, field, protoBytes)
+	b = protoAppendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// protoAppendString appends a string field. Empty values are omitted, as in proto3.
+func protoAppendString(b []byte, field int, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protoAppendTag(b, field, protoBytes)
+	b = protoAppendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// errProtoTruncated is returned when decoding a message which ends part way through a field.
+var errProtoTruncated = errors.New("proto: truncated message")
+
+// protoDecode calls fn for each field of an encoded message in order. Varint and fixed64 values are passed as v, and
+// length-delimited values as data. Unsupported wire types result in an error.
+func protoDecode(b []byte, fn func(field, wireType int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errProtoTruncated
+		}
+		b = b[n:]
+		field, wireType := int(tag>>3), int(tag&7)
+
+		var v uint64
+		var data []byte
+		switch wireType {
+		case protoVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errProtoTruncated
+			}
+			b = b[n:]
+		case protoFixed64:
+			if len(b) < 8 {
+				return errProtoTruncated
+			}
+			v = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case protoBytes:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return errProtoTruncated
+			}
+			data = b[n : n+int(length)]
+			b = b[n+int(length):]
+		default:
+			return fmt.Errorf("proto: unsupported wire type %d", wireType)
+		}
+
+		if err := fn(field, wireType, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// protoDecodeVarints returns the values of a repeated varint field, which may be either packed into a single
+// length-delimited value or encoded as individual varints.
+func protoDecodeVarints(wireType int, v uint64, data []byte) ([]uint64, error) {
+	if wireType == protoVarint {
+		return []uint64{v}, nil
+	}
+	var values []uint64
+	for len(data) > 0 {
+		value, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errProtoTruncated
+		}
+		values = append(values, value)
+		data = data[n:]
+	}
+	return values, nil
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket which limits the rate of messages written by a Logger. Messages which exceed the rate
+// are suppressed and a single summary message is written once the window in which they were suppressed closes. The
+// refill and the window are both measured with now(), and the end of the window is scheduled with the Clock.
+type rateLimiter struct {
+	mu         sync.Mutex
+	rate       float64 // tokens added per second
+	burst      float64
+	tokens     float64
+	last       time.Time
+	window     time.Duration
+	windowEnd  time.Time
+	suppressed int
+	// stopTimer stops the scheduled summary of the current window.
+	stopTimer func() bool
+}
+
+// SetRateLimit limits the Logger to writing n messages per the provided duration, allowing bursts of up to burst
+// messages. Messages exceeding the rate are dropped, and a "N messages suppressed" message is written at the end of
+// the window in which they were dropped. An n of 0 or less removes the rate limit.
+func (l *Logger) SetRateLimit(n int, per time.Duration, burst int) {
+	if n <= 0 || per <= 0 {
+		l.rateLimiter = nil
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.rateLimiter = &rateLimiter{
+		rate:   float64(n) / per.Seconds(),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now(),
+		window: per,
+	}
+}
+
+// allow reports whether a message from the Logger may be written, consuming a token if so. If the window of a previous
+// suppression has closed, its summary is written first.
+func (r *rateLimiter) allow(l *Logger) bool {
+	r.mu.Lock()
+	current := now()
+	closed := r.closeWindow(current)
+
+	// refill the bucket for the time elapsed since the last message
+	r.tokens += current.Sub(r.last).Seconds() * r.rate
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.last = current
+
+	allowed := r.tokens >= 1
+	if allowed {
+		r.tokens--
+	} else {
+		// the first suppressed message opens a window, at the end of which a summary is written
+		if r.suppressed == 0 {
+			r.windowEnd = current.Add(r.window)
+			r.schedule(l, r.window)
+		}
+		r.suppressed++
+	}
+	r.mu.Unlock()
+
+	r.summarise(l, closed)
+	return allowed
+}
+
+// schedule writes the summary of the current window after d, in case no further messages are logged to close it. r.mu
+// must be held.
+func (r *rateLimiter) schedule(l *Logger, d time.Duration) {
+	if r.stopTimer != nil {
+		r.stopTimer()
+	}
+	r.stopTimer = afterFunc(d, func() {
+		r.mu.Lock()
+		current := now()
+		if r.suppressed > 0 && current.Before(r.windowEnd) {
+			// the window has not closed according to now(), i.e. because a Clock which is not a TimerClock has not advanced
+			r.schedule(l, r.windowEnd.Sub(current))
+			r.mu.Unlock()
+			return
+		}
+		closed := r.closeWindow(current)
+		r.mu.Unlock()
+		r.summarise(l, closed)
+	})
+}
+
+// closeWindow closes the suppression window if it has ended by current, returning the number of messages suppressed
+// during it. r.mu must be held.
+func (r *rateLimiter) closeWindow(current time.Time) int {
+	if r.suppressed == 0 || current.Before(r.windowEnd) {
+		return 0
+	}
+	suppressed := r.suppressed
+	r.suppressed = 0
+	if r.stopTimer != nil {
+		r.stopTimer()
+		r.stopTimer = nil
+	}
+	return suppressed
+}
+
+// summarise writes the number of messages suppressed during a window which has just closed.
+func (r *rateLimiter) summarise(l *Logger, suppressed int) {
+	if suppressed > 0 && l.IsEnabled() {
+		l.enqueue(strconv.Itoa(suppressed)+" messages suppressed", false, nil)
+	}
+}
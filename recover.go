@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Recover logs the value and stack trace of a panic to l, waits for it to be written and then panics again with the
+// same value, so that a panic is never lost in the queue when the program crashes. It must be deferred directly:
+//
+//	defer logger.Recover(Error)
+//
+// The message bypasses the Logger's sampling, rate limiting and duplicate suppression, but is not logged if the Logger
+// is disabled.
+func Recover(l *Logger) {
+	if r := recover(); r != nil {
+		logPanic(l, r)
+		panic(r)
+	}
+}
+
+// RecoverAndContinue logs a panic in the same way as Recover, but does not panic again, allowing the function which
+// deferred it to return normally, i.e. so that a panicking worker does not bring down its process.
+func RecoverAndContinue(l *Logger) {
+	if r := recover(); r != nil {
+		logPanic(l, r)
+	}
+}
+
+// logPanic logs a recovered panic value along with the current stack, then flushes the queue.
+func logPanic(l *Logger, r interface{}) {
+	if l.IsEnabled() {
+		l.enqueue(fmt.Sprintf("panic: %v\n%s", r, debug.Stack()), false, nil)
+	}
+	Flush(FlushTimeout)
+}
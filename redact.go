@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"regexp"
+	"sync"
+)
+
+// RedactedText replaces any text which is matched by one of the built-in redactors.
+const RedactedText = "[REDACTED]"
+
+// RedactorFunc replaces any sensitive text in the provided string, returning the result. Redactors are applied to every
+// message and field value once it has been composed, before it reaches any Writer or Sink.
+type RedactorFunc func(string) string
+
+var (
+	redactors   []RedactorFunc
+	redactorsMu sync.RWMutex
+
+	apiKeyPattern      = regexp.MustCompile(`(?i)\b((?:api[_-]?key|access[_-]?key|secret|token|password|passwd)["']?\s*[=:]\s*["']?)[^\s"',;]+|\b(?:AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|sk_(?:live|test)_[A-Za-z0-9]{16,}|xox[abprs]-[A-Za-z0-9-]{10,})\b`)
+	bearerPattern      = regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+	creditCardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	emailPattern       = regexp.MustCompile(`(?i)\b[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}\b`)
+	creditCardSeparate = regexp.MustCompile(`[ -]`)
+)
+
+var (
+	// RedactAPIKeys is a built-in redactor which masks the values of key=value or key: value pairs where the key names
+	// an API key, secret, token or password, as well as well-known access key formats (AWS, GitHub, Stripe & Slack).
+	RedactAPIKeys RedactorFunc = func(s string) string {
+		return apiKeyPattern.ReplaceAllString(s, "${1}"+RedactedText)
+	}
+	// RedactBearerTokens is a built-in redactor which masks the token following "Bearer", i.e. in an Authorization
+	// header.
+	RedactBearerTokens RedactorFunc = func(s string) string {
+		return bearerPattern.ReplaceAllString(s, "${1}"+RedactedText)
+	}
+	// RedactCreditCards is a built-in redactor which masks sequences of 13 to 19 digits, optionally separated by spaces
+	// or dashes, which pass the Luhn checksum.
+	RedactCreditCards RedactorFunc = func(s string) string {
+		return creditCardPattern.ReplaceAllStringFunc(s, func(match string) string {
+			if !luhnValid(creditCardSeparate.ReplaceAllString(match, "")) {
+				return match
+			}
+			return RedactedText
+		})
+	}
+	// RedactEmails is a built-in redactor which masks email addresses.
+	RedactEmails RedactorFunc = func(s string) string {
+		return emailPattern.ReplaceAllString(s, RedactedText)
+	}
+)
+
+// AddRedactor registers redactors which are applied, in the order they were added, to the messages and field values of
+// every Logger, i.e. AddRedactor(RedactAPIKeys, RedactBearerTokens) masks credentials before they reach any Writer.
+func AddRedactor(r ...RedactorFunc) {
+	redactorsMu.Lock()
+	redactors = append(redactors, r...)
+	redactorsMu.Unlock()
+}
+
+// ClearRedactors removes all registered redactors.
+func ClearRedactors() {
+	redactorsMu.Lock()
+	redactors = nil
+	redactorsMu.Unlock()
+}
+
+// redactEntry applies the registered redactors, followed by the Logger's scrub rules, to the message and field values
+// of an Entry. The fields are copied rather than modified in place, and any field whose value is
+// altered is replaced by a String Field.
+func redactEntry(e *Entry, rules ScrubRules) {
+	redactorsMu.RLock()
+	chain := redactors
+	redactorsMu.RUnlock()
+	if len(rules) > 0 {
+		chain = append(append([]RedactorFunc(nil), chain...), rules.Scrub)
+	}
+	if len(chain) == 0 {
+		return
+	}
+
+	redact := func(s string) string {
+		for _, r := range chain {
+			s = r(s)
+		}
+		return s
+	}
+
+	e.Message = redact(e.Message)
+	if len(e.Fields) == 0 {
+		return
+	}
+
+	fields := make([]Field, len(e.Fields))
+	for i, f := range e.Fields {
+		value := f.String()
+		if redacted := redact(value); redacted != value {
+			f = String(f.Key, redacted)
+		}
+		fields[i] = f
+	}
+	e.Fields = fields
+}
+
+// luhnValid reports whether a string of digits passes the Luhn checksum.
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
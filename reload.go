@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var configWatcher struct {
+	mu   sync.Mutex
+	stop chan struct{}
+}
+
+// ReloadConfig loads the config file most recently passed to Configure again and applies any changes live, i.e. to
+// enable or disable loggers, swap their outputs or change formats. Messages which were already queued are written to
+// the outputs they were logged to, and files which are no longer used by any Logger are closed once they have been.
+func ReloadConfig() error {
+	configMu.Lock()
+	path := configPath
+	configMu.Unlock()
+	if path == "" {
+		return errors.New("no config file has been loaded")
+	}
+	_, err := Configure(path)
+	return err
+}
+
+// StartConfigWatcher loads the config file at path with Configure, then checks it for changes every interval and
+// reloads it when its modification time or size changes. Config files which fail to load are reported with the Internal
+// logger and leave the current configuration in place. Any previous watcher is stopped.
+func StartConfigWatcher(path string, interval time.Duration) error {
+	if _, err := Configure(path); err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	StopConfigWatcher()
+	stop := make(chan struct{})
+	configWatcher.mu.Lock()
+	configWatcher.stop = stop
+	configWatcher.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		modTime, size := info.ModTime(), info.Size()
+		for {
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+
+			info, err := os.Stat(path)
+			if err != nil || (info.ModTime().Equal(modTime) && info.Size() == size) {
+				continue
+			}
+			modTime, size = info.ModTime(), info.Size()
+			if _, err := Configure(path); err != nil {
+				Internal.Logf("failed to reload config: %s", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// StopConfigWatcher stops watching the config file for changes.
+func StopConfigWatcher() {
+	configWatcher.mu.Lock()
+	if configWatcher.stop != nil {
+		close(configWatcher.stop)
+		configWatcher.stop = nil
+	}
+	configWatcher.mu.Unlock()
+}
+
+// closeUnusedConfigFiles closes the files opened by Configure which are no longer the Writer of any Logger, once the
+// messages already queued for them have been written.
+func closeUnusedConfigFiles() {
+	loggerList := registeredLoggers()
+
+	configMu.Lock()
+	var unused []*File
+	for path, f := range configFiles {
+		used := false
+		for _, l := range loggerList {
+			if writesTo(l.currentWriter(), f) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			unused = append(unused, f)
+			delete(configFiles, path)
+		}
+	}
+	configMu.Unlock()
+
+	if len(unused) == 0 {
+		return
+	}
+	afterQueued(func() {
+		for _, f := range unused {
+			f.Close()
+		}
+	})
+}
+
+// writesTo reports whether w is target, or a MultiSink which writes to target.
+func writesTo(w io.Writer, target io.Writer) bool {
+	multi, ok := w.(*MultiSink)
+	if !ok {
+		return w == target
+	}
+	found := false
+	multi.each(func(t multiTarget) error {
+		if t.writer == target {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// afterQueued calls fn once every message queued so far has been written, including any which have been spilled to
+// disk.
+func afterQueued(fn func()) {
+	if deterministic {
+		fn()
+		return
+	}
+	item := queueItem{barrier: fn}
+	go enqueueBarrier(item)
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReloadConfigWhileLogging(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "logging.json")
+	writeConfig := func(i int) {
+		config := fmt.Sprintf(`{
+			"padding": %t,
+			"grouping": %t,
+			"multiline_indent": %t,
+			"category_width": %d,
+			"loggers": [{
+				"category": "RELOADED",
+				"outputs": [{"path": %q}],
+				"timestamp": %q,
+				"utc": %t,
+				"delta": %t,
+				"max_message_size": %d,
+				"sequence": %t,
+				"tags": [%q]
+			}]
+		}`, i%2 == 0, i%3 == 0, i%2 == 1, i%4, filepath.Join(dir, fmt.Sprintf("%d.log", i%2)),
+			[]string{"rfc3339", "unix_milli", "15:04:05"}[i%3], i%2 == 0, i%3 == 1, 10*(i%3), i%2 == 1,
+			fmt.Sprintf("tag%d", i%3))
+		if err := ioutil.WriteFile(configPath, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeConfig(0)
+	loggers, err := Configure(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := loggers["RELOADED"]
+	defer RemoveLogger(l)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				l.Logf("message with a long body %d", i)
+				l.LogFields("fields", Int("worker", i))
+				l.HasTag("tag1")
+				l.Tags()
+				RemoveLogger(NewLogger(ioutil.Discard, "SCOPED", true))
+			}
+		}()
+	}
+
+	for i := 1; i <= 20; i++ {
+		writeConfig(i)
+		if err := ReloadConfig(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+	Flush(time.Second)
+
+	if tags := l.Tags(); len(tags) != 1 || tags[0] != "tag2" {
+		t.Errorf("expected the tags of the last config to be applied, got %v", tags)
+	}
+}
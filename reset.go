@@ -0,0 +1,68 @@
+package logger
+
+import "sync/atomic"
+
+// Reset restores the package to its initial state so that tests which exercise package-level settings do not affect
+// each other. Every Logger other than Internal, including the default loggers, is removed from the registry and IDs
+// start again from 1, the sequence counter is reset, padding, grouping and multi-line indentation are re-enabled, the
+// category width is no longer fixed, line wrapping is disabled, buffered logging is disabled and any messages waiting
+// in the buffered queue are discarded. Global hooks, exit hooks, transforms, redactors, hierarchy settings, category
+// groups, the write error handler, the Clock, deterministic mode, the recording of recent messages, the priority Level,
+// write batching, named counters, the statistics summary, silence watchdogs, the heartbeat and status lines are also
+// reset. Loggers which were removed keep their own settings and may still be used, but no longer count towards category
+// padding.
+func Reset() {
+	loggersMu.Lock()
+	loggers = make(map[*Logger]bool)
+	highestLoggerID = -1
+	loggersMu.Unlock()
+	defaultLoggersMu.Lock()
+	defaultLoggers = make(map[Level]*Logger)
+	defaultLoggersMu.Unlock()
+	atomic.StoreUint64(&sequence, 0)
+
+	SetBuffered(false)
+	for _, ok := logQueue.pop(); ok; _, ok = logQueue.pop() {
+	}
+	for drained := false; !drained; {
+		select {
+		case <-logQueuePriority:
+		default:
+			drained = true
+		}
+	}
+
+	priorityLevel = LevelError
+	SetCategoryGrouping(true)
+	SetMultilineIndent(true)
+	SetCategoryWidth(0)
+	SetLineWrapping(false)
+	SetWrapWidth(0)
+
+	ClearHooks()
+	ClearTransforms()
+	ClearRedactors()
+	ClearHierarchy()
+	ClearCategoryGroups()
+	exitHooksMu.Lock()
+	exitHooks = nil
+	exitHooksMu.Unlock()
+	OnWriteError(nil)
+	SetClock(nil)
+	SetDeterministic(false)
+	RecordRecent(0)
+	SetWriteBatching(0, 0)
+	clearCounters()
+	StopStatsSummary()
+	stopSilenceWatchdogs()
+	StopHeartbeat()
+	clearStatusLines()
+
+	Internal.SetEnabled(true)
+	Internal.ResetCount()
+	Internal.SetCounter("")
+	atomic.StoreInt64(&Internal.lastWrite, 0)
+	atomic.StoreInt64(&Internal.lastEntry, 0)
+	AddLogger(Internal)
+	SetCategoryPadding(true)
+}
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrRetryBufferFull is returned by a RetrySink when an entry cannot be buffered because MaxBuffered entries are already
+// waiting to be replayed. The entry is then handled by the Logger's ErrorPolicy.
+var ErrRetryBufferFull = errors.New("retry buffer full")
+
+// RetrySink wraps a Sink which writes to a network destination, buffering entries while the destination is failing and
+// replaying them in order from a background goroutine once it recovers. Replays are attempted with exponential backoff
+// and jitter, starting at InitialBackoff and doubling up to MaxBackoff. Entries are only reported as failed if the buffer
+// is full.
+type RetrySink struct {
+	Sink Sink
+	// MaxBuffered is the maximum number of entries held while the Sink is failing (default of 1000).
+	MaxBuffered int
+	// InitialBackoff is the delay before the first replay attempt (default of 100ms).
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between replay attempts (default of 30s).
+	MaxBackoff time.Duration
+
+	mu       sync.Mutex
+	pending  []Entry
+	retrying bool
+}
+
+// NewRetrySink creates a RetrySink wrapping sink with the default buffer size and backoff.
+func NewRetrySink(sink Sink) *RetrySink {
+	return &RetrySink{
+		Sink:           sink,
+		MaxBuffered:    1000,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// WriteEntry writes the entry to the wrapped Sink. If the Sink fails, or earlier entries are still waiting to be
+// replayed, the entry is buffered instead.
+func (s *RetrySink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	if s.retrying {
+		defer s.mu.Unlock()
+		return s.buffer(e)
+	}
+	s.mu.Unlock()
+
+	if err := s.Sink.WriteEntry(e); err == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.buffer(e); err != nil {
+		return err
+	}
+	if !s.retrying {
+		s.retrying = true
+		go s.replay()
+	}
+	return nil
+}
+
+// Write implements io.Writer so that a RetrySink can be used as a Logger's Writer. Raw writes are sent as LevelInfo
+// entries without a category.
+func (s *RetrySink) Write(p []byte) (int, error) {
+	message := bytes.TrimSpace(p)
+	if len(message) == 0 {
+		return len(p), nil
+	}
+	e := Entry{
+		Time:    now(),
+		Level:   LevelInfo,
+		Message: string(message),
+	}
+	if err := s.WriteEntry(e); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Pending returns the number of entries waiting to be replayed.
+func (s *RetrySink) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// buffer queues an entry to be replayed. s.mu must be held.
+func (s *RetrySink) buffer(e Entry) error {
+	max := s.MaxBuffered
+	if max <= 0 {
+		max = 1000
+	}
+	if len(s.pending) >= max {
+		return ErrRetryBufferFull
+	}
+	s.pending = append(s.pending, e)
+	return nil
+}
+
+// replay writes buffered entries to the Sink in order, backing off while it continues to fail. It returns once the
+// buffer has been drained.
+func (s *RetrySink) replay() {
+	initial := s.InitialBackoff
+	if initial <= 0 {
+		initial = 100 * time.Millisecond
+	}
+	max := s.MaxBackoff
+	if max < initial {
+		max = initial
+	}
+
+	backoff := initial
+	for {
+		// sleep for between half and all of the backoff so that many failing sinks don't retry in lockstep
+		time.Sleep(backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)))
+
+		for {
+			s.mu.Lock()
+			if len(s.pending) == 0 {
+				s.retrying = false
+				s.mu.Unlock()
+				return
+			}
+			e := s.pending[0]
+			s.mu.Unlock()
+
+			if err := s.Sink.WriteEntry(e); err != nil {
+				break
+			}
+
+			s.mu.Lock()
+			s.pending = s.pending[1:]
+			s.mu.Unlock()
+			backoff = initial
+		}
+
+		backoff *= 2
+		if backoff > max {
+			backoff = max
+		}
+	}
+}
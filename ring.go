@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// RingSink is a Sink which retains the most recent entries written to it in memory, overwriting the oldest entry once
+// it is full. It is useful for keeping context which is too verbose to persist, so that it can be dumped when something
+// goes wrong.
+type RingSink struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// NewRingSink creates a RingSink which retains the last n entries.
+func NewRingSink(n int) *RingSink {
+	if n < 1 {
+		n = 1
+	}
+	return &RingSink{entries: make([]Entry, n)}
+}
+
+// WriteEntry stores the entry, overwriting the oldest entry if the RingSink is full.
+func (s *RingSink) WriteEntry(e Entry) error {
+	s.mu.Lock()
+	s.entries[s.next] = e
+	s.next++
+	if s.next == len(s.entries) {
+		s.next = 0
+		s.full = true
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// Write implements io.Writer so that a RingSink can be used as a Logger's Writer. Raw writes are stored as LevelInfo
+// entries without a category.
+func (s *RingSink) Write(p []byte) (int, error) {
+	message := bytes.TrimSpace(p)
+	if len(message) == 0 {
+		return len(p), nil
+	}
+	s.WriteEntry(Entry{
+		Time:    now(),
+		Level:   LevelInfo,
+		Message: string(message),
+	})
+	return len(p), nil
+}
+
+// Entries returns the retained entries, oldest first.
+func (s *RingSink) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.full {
+		return append([]Entry(nil), s.entries[:s.next]...)
+	}
+	return append(append([]Entry(nil), s.entries[s.next:]...), s.entries[:s.next]...)
+}
+
+// Dump writes the retained entries to w as text, oldest first.
+func (s *RingSink) Dump(w io.Writer) error {
+	for _, e := range s.Entries() {
+		if _, err := w.Write(TextEncoder(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	recent   *RingSink
+	recentMu sync.RWMutex
+)
+
+// RecordRecent retains the last n messages logged by every Logger in memory, so that they can be written out by
+// DumpRecent, i.e. from a panic handler. Messages are recorded even if their Logger is disabled or they are dropped by
+// sampling, rate limiting or duplicate suppression, so verbose DEBUG context is available without being persisted.
+// Messages which are written are recorded with the same Sequence number, and other messages with a Sequence of 0. An n
+// of 0 or less stops recording and discards the retained messages.
+func RecordRecent(n int) {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	if n <= 0 {
+		recent = nil
+		return
+	}
+	recent = NewRingSink(n)
+}
+
+// DumpRecent writes the messages retained by RecordRecent to w, oldest first.
+func DumpRecent(w io.Writer) error {
+	recentMu.RLock()
+	r := recent
+	recentMu.RUnlock()
+	if r == nil {
+		return nil
+	}
+	return r.Dump(w)
+}
+
+// recordRecent records the entry of a message if RecordRecent has been enabled. The entry is only created when it is
+// recorded.
+func recordRecent(entry func() Entry) {
+	recentMu.RLock()
+	r := recent
+	recentMu.RUnlock()
+	if r != nil {
+		r.WriteEntry(entry())
+	}
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestRecordRecentKeepsSequence(t *testing.T) {
+	RecordRecent(10)
+	defer RecordRecent(0)
+
+	sink := NewRingSink(10)
+	l := NewLogger(sink, "RECENT", true)
+	defer RemoveLogger(l)
+	l.Log("first")
+	l.Disable()
+	l.Log("disabled")
+	l.Enable()
+	l.Log("second")
+	Flush(time.Second)
+
+	written := sink.Entries()
+	if len(written) != 2 || written[1].Sequence != written[0].Sequence+1 {
+		t.Fatalf("expected the written entries to have consecutive Sequence numbers, got %+v", written)
+	}
+
+	// other loggers may be recording concurrently, so only this logger's entries are checked
+	recentMu.RLock()
+	r := recent
+	recentMu.RUnlock()
+	var recorded []Entry
+	for _, e := range r.Entries() {
+		if e.Category == "RECENT" {
+			recorded = append(recorded, e)
+		}
+	}
+	if len(recorded) != 3 {
+		t.Fatalf("expected 3 recorded entries, got %+v", recorded)
+	}
+	if recorded[0].Sequence != written[0].Sequence || recorded[2].Sequence != written[1].Sequence {
+		t.Errorf("expected recorded entries to share the Sequence of the written entries, got %+v", recorded)
+	}
+	if recorded[1].Message != "disabled" || recorded[1].Sequence != 0 {
+		t.Errorf("expected the unwritten entry to be recorded without a Sequence, got %+v", recorded[1])
+	}
+	if err := DumpRecent(ioutil.Discard); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ringSlot is a single slot of a ringQueue. seq records which lap of the ring the slot is ready for, so that producers
+// and the consumer can claim slots without a lock.
+type ringSlot struct {
+	seq  uint64
+	item queueItem
+}
+
+// ringQueue is a bounded, lock-free queue of messages with many producers (the goroutines logging) and a single
+// consumer (the poller). Producers claim a slot by advancing tail with a compare-and-swap, so that concurrent producers
+// only contend on a single word rather than a channel lock. Producers which find the queue full wait on space, which is
+// only signalled while a producer is waiting, so the fast path never takes a lock.
+type ringQueue struct {
+	slots []ringSlot
+	mask  uint64
+	head  uint64
+	tail  uint64
+
+	// notify wakes the consumer once a message has been pushed.
+	notify chan struct{}
+	// full is the number of pushes which found the queue full.
+	full uint64
+
+	mu      sync.Mutex
+	space   *sync.Cond
+	waiters int32
+}
+
+// newRingQueue creates a ringQueue which holds at least size messages, rounded up to a power of two.
+func newRingQueue(size int) *ringQueue {
+	capacity := uint64(1)
+	for capacity < uint64(size) {
+		capacity <<= 1
+	}
+	r := &ringQueue{
+		slots:  make([]ringSlot, capacity),
+		mask:   capacity - 1,
+		notify: make(chan struct{}, 1),
+	}
+	for i := range r.slots {
+		r.slots[i].seq = uint64(i)
+	}
+	r.space = sync.NewCond(&r.mu)
+	return r
+}
+
+// push adds an item to the queue, reporting its position, or false if the queue is full.
+func (r *ringQueue) push(item queueItem) (uint64, bool) {
+	for {
+		pos := atomic.LoadUint64(&r.tail)
+		slot := &r.slots[pos&r.mask]
+		seq := atomic.LoadUint64(&slot.seq)
+		switch {
+		case seq == pos:
+			if atomic.CompareAndSwapUint64(&r.tail, pos, pos+1) {
+				slot.item = item
+				atomic.StoreUint64(&slot.seq, pos+1)
+				select {
+				case r.notify <- struct{}{}:
+				default:
+				}
+				return pos, true
+			}
+		case seq < pos:
+			// the slot has not been consumed since the previous lap
+			atomic.AddUint64(&r.full, 1)
+			return 0, false
+		}
+	}
+}
+
+// pushWait adds an item to the queue, waiting for space if it is full. The position of the item is returned.
+func (r *ringQueue) pushWait(item queueItem) uint64 {
+	for {
+		if pos, ok := r.push(item); ok {
+			return pos
+		}
+		r.wait(func() bool {
+			return atomic.LoadUint64(&r.tail)-atomic.LoadUint64(&r.head) < uint64(len(r.slots))
+		})
+	}
+}
+
+// pop removes the oldest item from the queue, reporting false if it is empty. pop is safe to call concurrently with the
+// poller, i.e. by Reset.
+func (r *ringQueue) pop() (queueItem, bool) {
+	for {
+		pos := atomic.LoadUint64(&r.head)
+		slot := &r.slots[pos&r.mask]
+		seq := atomic.LoadUint64(&slot.seq)
+		switch {
+		case seq == pos+1:
+			if atomic.CompareAndSwapUint64(&r.head, pos, pos+1) {
+				item := slot.item
+				slot.item = queueItem{}
+				atomic.StoreUint64(&slot.seq, pos+r.mask+1)
+				if atomic.LoadInt32(&r.waiters) > 0 {
+					r.mu.Lock()
+					r.space.Broadcast()
+					r.mu.Unlock()
+				}
+				return item, true
+			}
+		case seq < pos+1:
+			// the slot has not been pushed yet
+			return queueItem{}, false
+		}
+	}
+}
+
+// waitConsumed waits until the item pushed at pos has been removed from the queue.
+func (r *ringQueue) waitConsumed(pos uint64) {
+	if atomic.LoadUint64(&r.head) > pos {
+		return
+	}
+	r.wait(func() bool {
+		return atomic.LoadUint64(&r.head) > pos
+	})
+}
+
+// wait blocks until ready reports true, which is checked each time an item is removed from the queue.
+func (r *ringQueue) wait(ready func() bool) {
+	r.mu.Lock()
+	atomic.AddInt32(&r.waiters, 1)
+	for !ready() {
+		r.space.Wait()
+	}
+	atomic.AddInt32(&r.waiters, -1)
+	r.mu.Unlock()
+}
+
+// len returns the number of items in the queue.
+func (r *ringQueue) len() int {
+	head := atomic.LoadUint64(&r.head)
+	tail := atomic.LoadUint64(&r.tail)
+	if tail < head {
+		return 0
+	}
+	return int(tail - head)
+}
+
+// cap returns the number of items the queue can hold.
+func (r *ringQueue) cap() int {
+	return len(r.slots)
+}
+
+// fullCount returns the number of pushes which found the queue full.
+func (r *ringQueue) fullCount() uint64 {
+	return atomic.LoadUint64(&r.full)
+}
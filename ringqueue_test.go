@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// sequencedItem returns a queueItem identified by seq.
+func sequencedItem(seq uint64) queueItem {
+	return queueItem{entry: Entry{Sequence: seq}}
+}
+
+func TestRingQueueWrapAround(t *testing.T) {
+	r := newRingQueue(3)
+	if r.cap() != 4 {
+		t.Fatalf("expected the capacity to be rounded up to 4, got %d", r.cap())
+	}
+
+	// push and pop enough items to lap the ring several times, keeping it partially full so positions wrap mid-queue
+	var pushed, popped uint64
+	for lap := 0; lap < 10; lap++ {
+		for r.len() < r.cap() {
+			pushed++
+			if _, ok := r.push(sequencedItem(pushed)); !ok {
+				t.Fatalf("push %d failed with %d items queued", pushed, r.len())
+			}
+		}
+		if _, ok := r.push(sequencedItem(0)); ok {
+			t.Fatal("expected a push to a full queue to fail")
+		}
+		for i := 0; i < 3; i++ {
+			item, ok := r.pop()
+			if !ok {
+				t.Fatal("expected an item to be popped")
+			}
+			popped++
+			if item.entry.Sequence != popped {
+				t.Fatalf("popped item %d, expected %d", item.entry.Sequence, popped)
+			}
+		}
+	}
+	for {
+		item, ok := r.pop()
+		if !ok {
+			break
+		}
+		popped++
+		if item.entry.Sequence != popped {
+			t.Fatalf("popped item %d, expected %d", item.entry.Sequence, popped)
+		}
+	}
+	if popped != pushed || r.len() != 0 {
+		t.Errorf("popped %d of %d items, %d left", popped, pushed, r.len())
+	}
+	if r.fullCount() != 10 {
+		t.Errorf("expected 10 full pushes, got %d", r.fullCount())
+	}
+}
+
+func TestRingQueueConcurrentPushPop(t *testing.T) {
+	const producers, perProducer = 8, 2000
+	r := newRingQueue(16)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				r.pushWait(sequencedItem(uint64(p*perProducer + i)))
+			}
+		}(p)
+	}
+
+	// a single consumer, as with the poller, must see every item once and each producer's items in order
+	last := make([]int, producers)
+	for p := range last {
+		last[p] = -1
+	}
+	seen := make([]bool, producers*perProducer)
+	for received := 0; received < producers*perProducer; {
+		item, ok := r.pop()
+		if !ok {
+			<-r.notify
+			continue
+		}
+		received++
+		seq := int(item.entry.Sequence)
+		if seen[seq] {
+			t.Fatalf("item %d was popped twice", seq)
+		}
+		seen[seq] = true
+		p, i := seq/perProducer, seq%perProducer
+		if i <= last[p] {
+			t.Fatalf("producer %d item %d was popped after item %d", p, i, last[p])
+		}
+		last[p] = i
+	}
+	wg.Wait()
+	if _, ok := r.pop(); ok {
+		t.Error("expected the queue to be empty")
+	}
+}
+
+func TestRingQueuePushWaitsWhenFull(t *testing.T) {
+	r := newRingQueue(2)
+	r.pushWait(sequencedItem(1))
+	r.pushWait(sequencedItem(2))
+
+	pushed := make(chan uint64)
+	go func() {
+		pushed <- r.pushWait(sequencedItem(3))
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("pushWait returned while the queue was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if item, _ := r.pop(); item.entry.Sequence != 1 {
+		t.Fatalf("popped item %d, expected 1", item.entry.Sequence)
+	}
+	select {
+	case pos := <-pushed:
+		if pos != 2 {
+			t.Errorf("expected the waiting item to be pushed at position 2, got %d", pos)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pushWait was not woken once space was available")
+	}
+	for _, expected := range []uint64{2, 3} {
+		if item, _ := r.pop(); item.entry.Sequence != expected {
+			t.Errorf("popped item %d, expected %d", item.entry.Sequence, expected)
+		}
+	}
+}
+
+func TestRingQueueWaitConsumed(t *testing.T) {
+	r := newRingQueue(4)
+	r.pushWait(sequencedItem(1))
+	pos := r.pushWait(sequencedItem(2))
+
+	consumed := make(chan struct{})
+	go func() {
+		r.waitConsumed(pos)
+		close(consumed)
+	}()
+
+	// popping the earlier item must not release a wait for the later one
+	r.pop()
+	select {
+	case <-consumed:
+		t.Fatal("waitConsumed returned before its item was popped")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if item, _ := r.pop(); item.entry.Sequence != 2 {
+		t.Fatalf("popped item %d, expected 2", item.entry.Sequence)
+	}
+	select {
+	case <-consumed:
+	case <-time.After(time.Second):
+		t.Fatal("waitConsumed was not woken once its item was popped")
+	}
+
+	// waiting for an item which has already been consumed returns immediately
+	r.waitConsumed(pos)
+}
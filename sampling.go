@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// Sampler decides whether each message logged by a Logger is written. Messages which are not sampled are counted as
+// dropped.
+type Sampler interface {
+	Sample() bool
+}
+
+// SamplerFunc allows an ordinary function to be used as a Sampler.
+type SamplerFunc func() bool
+
+// Sample calls f.
+func (f SamplerFunc) Sample() bool {
+	return f()
+}
+
+// everyN samples the first message and every nth message after it.
+type everyN struct {
+	n       uint64
+	counter uint64
+}
+
+// EveryN creates a Sampler which writes the first of every n messages. An n of 1 or less samples every message.
+func EveryN(n int) Sampler {
+	if n < 1 {
+		n = 1
+	}
+	return &everyN{n: uint64(n)}
+}
+
+func (s *everyN) Sample() bool {
+	return (atomic.AddUint64(&s.counter, 1)-1)%s.n == 0
+}
+
+// Probability creates a Sampler which writes each message with the probability p, where p is between 0 and 1.
+func Probability(p float64) Sampler {
+	return SamplerFunc(func() bool {
+		return rand.Float64() < p
+	})
+}
+
+// SetSampling sets the Sampler used to decide which of the Logger's messages are written, so that chatty categories can
+// remain enabled without flooding the output, i.e. Info.SetSampling(logger.EveryN(100)). Passing nil disables sampling.
+func (l *Logger) SetSampling(sampler Sampler) {
+	l.sampler = sampler
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// ScrubRule is a named regular expression whose matches are replaced in messages and field values, i.e. to remove
+// personal data in accordance with a GDPR policy. The Replacement may refer to submatches using the $1 or ${name}
+// syntax of regexp.Regexp.ReplaceAllString.
+type ScrubRule struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// NewScrubRule compiles pattern into a ScrubRule.
+func NewScrubRule(name, pattern, replacement string) (ScrubRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ScrubRule{}, fmt.Errorf("failed to compile scrub rule %q: %s", name, err)
+	}
+	return ScrubRule{Name: name, Pattern: re, Replacement: replacement}, nil
+}
+
+// ScrubRules is an ordered set of ScrubRules. Its Scrub method is a RedactorFunc, so a set of rules can be applied to
+// every Logger with AddRedactor(rules.Scrub), or to a single Logger with SetScrubRules.
+type ScrubRules []ScrubRule
+
+// Scrub applies each rule to s in order, returning the result.
+func (r ScrubRules) Scrub(s string) string {
+	for _, rule := range r {
+		s = rule.Pattern.ReplaceAllString(s, rule.Replacement)
+	}
+	return s
+}
+
+// LoadScrubRules reads a JSON array of rules in the form {"name": "...", "pattern": "...", "replacement": "..."} and
+// compiles them into ScrubRules, preserving their order.
+func LoadScrubRules(r io.Reader) (ScrubRules, error) {
+	var raw []struct {
+		Name        string `json:"name"`
+		Pattern     string `json:"pattern"`
+		Replacement string `json:"replacement"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode scrub rules: %s", err)
+	}
+
+	rules := make(ScrubRules, 0, len(raw))
+	for _, rr := range raw {
+		rule, err := NewScrubRule(rr.Name, rr.Pattern, rr.Replacement)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// SetScrubRules sets the rules applied to the messages and field values of the Logger, after any redactors registered
+// with AddRedactor. Passing no rules removes them.
+func (l *Logger) SetScrubRules(rules ScrubRules) {
+	l.scrubRules = rules
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Reopener is implemented by Writers which write to a file and can reopen it, i.e. once logrotate has moved it aside.
+// Reopen is called for the Writer of every Logger by ReopenFiles, which is called when the process receives SIGHUP after
+// HandleSignals has been called.
+type Reopener interface {
+	Reopen() error
+}
+
+var signalsOnce sync.Once
+
+// HandleSignals follows the conventions of long-running daemons by listening for signals which control logging:
+//
+//	SIGUSR1  increase verbosity, enabling the logger with the next highest ID (see SetEnabledByID)
+//	SIGUSR2  decrease verbosity, disabling the enabled logger with the highest ID
+//	SIGHUP   reopen every Logger's Writer which implements Reopener
+//
+// The starting verbosity is the highest ID of any enabled logger. Calling HandleSignals more than once has no effect.
+// On platforms without SIGUSR1 and SIGUSR2, such as Windows, HandleSignals does nothing.
+func HandleSignals() {
+	signalsOnce.Do(func() {
+		if len(verbositySignals) == 0 {
+			return
+		}
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, verbositySignals...)
+		go func() {
+			for sig := range ch {
+				handleSignal(sig)
+			}
+		}()
+	})
+}
+
+// handleSignal applies the logging change requested by a signal.
+func handleSignal(sig os.Signal) {
+	switch sig {
+	case increaseVerbositySignal:
+		SetEnabledByID(currentVerbosity() + 1)
+	case decreaseVerbositySignal:
+		SetEnabledByID(currentVerbosity() - 1)
+	case reopenSignal:
+		if err := ReopenFiles(); err != nil {
+			Internal.Logf("failed to reopen writers: %s", err)
+		}
+	}
+}
+
+// currentVerbosity returns the highest ID of any enabled logger, or -1 if all loggers are disabled.
+func currentVerbosity() int {
+	highest := -1
+	for _, l := range registeredLoggers() {
+		if l.IsEnabled() && l.id > highest {
+			highest = l.id
+		}
+	}
+	return highest
+}
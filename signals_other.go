@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package logger
+
+import "os"
+
+// verbosity signals are unavailable on this platform, so HandleSignals does nothing.
+var (
+	increaseVerbositySignal os.Signal
+	decreaseVerbositySignal os.Signal
+	reopenSignal            os.Signal
+	verbositySignals        []os.Signal
+)
@@ -0,0 +1,15 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	increaseVerbositySignal os.Signal = syscall.SIGUSR1
+	decreaseVerbositySignal os.Signal = syscall.SIGUSR2
+	reopenSignal            os.Signal = syscall.SIGHUP
+	verbositySignals                  = []os.Signal{syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGHUP}
+)
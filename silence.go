@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SilenceFunc is called by a silence watchdog when the watched loggers have not logged a message for at least the
+// watchdog's timeout. silence is the time since the last message, or since the watchdog was started if none has been
+// logged.
+type SilenceFunc func(category string, silence time.Duration)
+
+// silenceWatchdogs are the timers of every running silence watchdog, so that they can be stopped by Reset.
+var silenceWatchdogs = struct {
+	mu     sync.Mutex
+	timers map[*clockTimer]bool
+}{
+	timers: make(map[*clockTimer]bool),
+}
+
+// WatchSilence starts a watchdog which calls alert if the Logger has not logged a message within timeout, i.e. to
+// notice that a critical pipeline has gone quiet. A nil alert logs a message with the Internal logger instead. alert is
+// called once per silence, and the watchdog is re-armed once the Logger logs again. Messages are counted once they are
+// queued to be written, so messages logged while the Logger is disabled or dropped by sampling, rate limiting or
+// filters do not end a silence. The timeout follows the Clock. The returned function stops the watchdog.
+func (l *Logger) WatchSilence(timeout time.Duration, alert SilenceFunc) func() {
+	return startSilenceWatchdog(l.Category.Name, timeout, alert, func() []*Logger {
+		return []*Logger{l}
+	})
+}
+
+// WatchCategorySilence starts a watchdog in the same way as WatchSilence, but which is satisfied by a message logged by
+// any registered Logger with the provided Category Name. The category is case sensitive, and loggers registered after
+// the watchdog is started are also watched.
+func WatchCategorySilence(category string, timeout time.Duration, alert SilenceFunc) func() {
+	return startSilenceWatchdog(category, timeout, alert, func() []*Logger {
+		var matched []*Logger
+		for _, l := range registeredLoggers() {
+			if l.Category.Name == category {
+				matched = append(matched, l)
+			}
+		}
+		return matched
+	})
+}
+
+// startSilenceWatchdog starts a watchdog over the loggers returned by watched, returning a function which stops it.
+func startSilenceWatchdog(category string, timeout time.Duration, alert SilenceFunc, watched func() []*Logger) func() {
+	if alert == nil {
+		alert = func(category string, silence time.Duration) {
+			Internal.Logf("no messages logged by %s for %s", category, silence.Round(time.Millisecond))
+		}
+	}
+
+	timer := &clockTimer{}
+	silenceWatchdogs.mu.Lock()
+	silenceWatchdogs.timers[timer] = true
+	silenceWatchdogs.mu.Unlock()
+
+	started := now()
+	// alerted is the time of the last message when alert was last called, so that it is called once per silence
+	var alerted time.Time
+	var check func()
+	check = func() {
+		last := started
+		for _, l := range watched() {
+			if t := l.lastEntryTime(); t.After(last) {
+				last = t
+			}
+		}
+		silence := now().Sub(last)
+		if silence < timeout {
+			timer.reset(timeout-silence, check)
+			return
+		}
+		if !last.Equal(alerted) {
+			alerted = last
+			alert(category, silence)
+		}
+		timer.reset(timeout, check)
+	}
+	timer.reset(timeout, check)
+
+	return func() {
+		silenceWatchdogs.mu.Lock()
+		delete(silenceWatchdogs.timers, timer)
+		silenceWatchdogs.mu.Unlock()
+		timer.stop()
+	}
+}
+
+// lastEntryTime returns the time of the last message queued to be written by the Logger, or the zero Time if none has.
+func (l *Logger) lastEntryTime() time.Time {
+	nanos := atomic.LoadInt64(&l.lastEntry)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// stopSilenceWatchdogs stops every running silence watchdog.
+func stopSilenceWatchdogs() {
+	silenceWatchdogs.mu.Lock()
+	for timer := range silenceWatchdogs.timers {
+		timer.stop()
+	}
+	silenceWatchdogs.timers = make(map[*clockTimer]bool)
+	silenceWatchdogs.mu.Unlock()
+}
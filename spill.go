@@ -0,0 +1,246 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// spillover writes messages to a segment file on disk when the buffered queue is full, then replays them into the queue
+// in order from a background goroutine as it drains. While any spilled messages are waiting to be replayed, new
+// messages are also spilled so that ordering is preserved, and barriers are held back until the messages spilled
+// before them have been replayed.
+var spillover struct {
+	mu       sync.Mutex
+	dir      string
+	enabled  bool
+	active   bool
+	file     *os.File
+	reader   *bufio.Reader
+	spilled  int
+	replayed int
+	loggers  map[int]*Logger
+	// drained is closed once the active segment file has been fully replayed.
+	drained  chan struct{}
+	barriers []spilledBarrier
+}
+
+// spilledBarrier is a barrier which is sent to the queue once the messages spilled before it have been replayed.
+type spilledBarrier struct {
+	// after is the number of messages which had been spilled when the barrier was queued.
+	after int
+	item  queueItem
+}
+
+// spilledItem is the on-disk form of a queued message. Field values are stored as text.
+type spilledItem struct {
+	LoggerID int            `json:"logger"`
+	Previous int64          `json:"previous,omitempty"`
+	Newline  bool           `json:"newline,omitempty"`
+	Sequence uint64         `json:"seq"`
+	Time     time.Time      `json:"time"`
+	Level    Level          `json:"level"`
+	Category string         `json:"category"`
+	Entry    string         `json:"entry"`
+	Fields   []spilledField `json:"fields,omitempty"`
+}
+
+type spilledField struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// SetSpillDir enables spilling messages to a temporary segment file in dir when the buffered queue is full, rather than
+// blocking the caller until there is space. Spilled messages are replayed in order once the queue drains, and the
+// segment file is removed once it has been fully replayed. An empty dir disables spilling for subsequent messages.
+// Spilling only applies when buffered logging has been enabled with SetBuffered.
+func SetSpillDir(dir string) {
+	spillover.mu.Lock()
+	spillover.dir = dir
+	spillover.enabled = dir != ""
+	spillover.mu.Unlock()
+}
+
+// SpillDepth returns the number of spilled messages waiting to be replayed into the buffered queue.
+func SpillDepth() int {
+	spillover.mu.Lock()
+	defer spillover.mu.Unlock()
+	return spillover.spilled - spillover.replayed
+}
+
+// enqueueBuffered sends a message to the buffered queue, spilling it to disk if the queue is full and spilling is
+// enabled.
+func enqueueBuffered(item queueItem) {
+	spillover.mu.Lock()
+	if spillover.active {
+		if spill(item) {
+			spillover.mu.Unlock()
+			return
+		}
+		// the message must not overtake those already spilled, so wait for them to be replayed
+		drained := spillover.drained
+		spillover.mu.Unlock()
+		<-drained
+		enqueueBuffered(item)
+		return
+	}
+	if !spillover.enabled {
+		spillover.mu.Unlock()
+		logQueue.pushWait(item)
+		return
+	}
+
+	if _, ok := logQueue.push(item); ok {
+		spillover.mu.Unlock()
+		return
+	}
+	spilled := spill(item)
+	spillover.mu.Unlock()
+
+	// write directly to the queue if the segment file could not be written to
+	if !spilled {
+		logQueue.pushWait(item)
+	}
+}
+
+// enqueueBarrier sends a barrier to the buffered queue. If messages have been spilled, the barrier is held back until
+// they have been replayed, so that it is not called before they have been written.
+func enqueueBarrier(item queueItem) {
+	spillover.mu.Lock()
+	if spillover.active {
+		spillover.barriers = append(spillover.barriers, spilledBarrier{after: spillover.spilled, item: item})
+		spillover.mu.Unlock()
+		return
+	}
+	spillover.mu.Unlock()
+	logQueue.pushWait(item)
+}
+
+// spill appends a message to the segment file, creating it and starting the replay goroutine if spilling is not already
+// active. It reports whether the message was spilled. spillover.mu must be held.
+func spill(item queueItem) bool {
+	if !spillover.active {
+		file, err := ioutil.TempFile(spillover.dir, "logger-spill-*.seg")
+		if err != nil {
+			return false
+		}
+		reader, err := os.Open(file.Name())
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+			return false
+		}
+		spillover.file = file
+		spillover.reader = bufio.NewReader(reader)
+		spillover.loggers = make(map[int]*Logger)
+		spillover.spilled, spillover.replayed = 0, 0
+		spillover.drained = make(chan struct{})
+		spillover.active = true
+		go replaySpilled(reader)
+	}
+
+	s := spilledItem{
+		LoggerID: item.logger.id,
+		Previous: item.previous,
+		Newline:  item.newline,
+		Sequence: item.entry.Sequence,
+		Time:     item.entry.Time,
+		Level:    item.entry.Level,
+		Category: item.entry.Category,
+		Entry:    item.entry.Message,
+	}
+	for _, f := range item.entry.Fields {
+		s.Fields = append(s.Fields, spilledField{Key: f.Key, Value: f.String()})
+	}
+	line, err := json.Marshal(s)
+	if err != nil {
+		return false
+	}
+	if _, err := spillover.file.Write(append(line, '\n')); err != nil {
+		return false
+	}
+
+	spillover.loggers[item.logger.id] = item.logger
+	spillover.spilled++
+	return true
+}
+
+// replaySpilled reads spilled messages back from the segment file and sends them to the buffered queue, blocking until
+// there is space for each. Barriers are sent once the messages spilled before them have been. Once every spilled
+// message has been replayed, the segment file is removed.
+func replaySpilled(file *os.File) {
+	for {
+		spillover.mu.Lock()
+		var ready []spilledBarrier
+		for len(spillover.barriers) > 0 && spillover.barriers[0].after <= spillover.replayed {
+			ready = append(ready, spillover.barriers[0])
+			spillover.barriers = spillover.barriers[1:]
+		}
+		done := spillover.replayed == spillover.spilled
+		if done {
+			ready = append(ready, spillover.barriers...)
+			spillover.barriers = nil
+			spillover.active = false
+			close(spillover.drained)
+			spillover.file.Close()
+			file.Close()
+			os.Remove(file.Name())
+		}
+		reader := spillover.reader
+		spillover.mu.Unlock()
+
+		for _, barrier := range ready {
+			logQueue.pushWait(barrier.item)
+		}
+		if done {
+			return
+		}
+
+		var s spilledItem
+		line, err := reader.ReadBytes('\n')
+		decodeErr := json.Unmarshal(line, &s)
+
+		spillover.mu.Lock()
+		l := spillover.loggers[s.LoggerID]
+		spillover.mu.Unlock()
+
+		if err == nil && decodeErr == nil && l != nil {
+			logQueue.pushWait(s.queueItem(l))
+		}
+
+		spillover.mu.Lock()
+		spillover.replayed++
+		spillover.mu.Unlock()
+	}
+}
+
+// queueItem reconstructs the queued message for the Logger which spilled it.
+func (s spilledItem) queueItem(l *Logger) queueItem {
+	var fields []Field
+	for _, f := range s.Fields {
+		fields = append(fields, String(f.Key, f.Value))
+	}
+	l.stateMu.RLock()
+	writer, timestamp := l.Writer, l.Timestamp
+	l.stateMu.RUnlock()
+	return queueItem{
+		logger:    l,
+		writer:    writer,
+		category:  l.Category,
+		timestamp: timestamp,
+		previous:  s.Previous,
+		newline:   s.Newline,
+		order:     l.Order,
+		entry: Entry{
+			Sequence: s.Sequence,
+			Time:     s.Time,
+			Level:    s.Level,
+			Category: s.Category,
+			Message:  s.Entry,
+			Fields:   fields,
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedWriter blocks every write until it is opened, so that the queues behind it fill up.
+type gatedWriter struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestFlushWaitsForSpilled(t *testing.T) {
+	SetBuffered(true)
+	SetSpillDir(t.TempDir())
+	defer SetBuffered(false)
+	defer SetSpillDir("")
+
+	w := &gatedWriter{gate: make(chan struct{})}
+	l := NewLogger(w, "SPILL", true)
+	// enough messages to fill the writer's queue and the buffered queue behind it
+	const count = 4 * 1024
+	for i := 0; i < count; i++ {
+		l.Log(i)
+	}
+	if SpillDepth() == 0 {
+		t.Fatal("expected messages to be spilled")
+	}
+
+	close(w.gate)
+	if !Flush(10 * time.Second) {
+		t.Fatal("flush timed out")
+	}
+
+	w.mu.Lock()
+	lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\n"), "\n")
+	w.mu.Unlock()
+	if len(lines) != count {
+		t.Fatalf("expected %d messages to be written before Flush returned, got %d", count, len(lines))
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, " "+strconv.Itoa(i)) {
+			t.Fatalf("message %d written out of order: %q", i, line)
+		}
+	}
+}
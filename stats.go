@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// LoggerStats is the statistics of a single Logger.
+type LoggerStats struct {
+	ID       int    `json:"id"`
+	Category string `json:"category"`
+	Level    string `json:"level"`
+	Enabled  bool   `json:"enabled"`
+	Count    int    `json:"count"`
+	Dropped  int64  `json:"dropped"`
+	// LastWrite is the time the Logger last wrote a message successfully, or the zero Time if it has not.
+	LastWrite time.Time `json:"last_write"`
+	Tags      []string  `json:"tags,omitempty"`
+}
+
+// QueueStats is the statistics of the logging queues.
+type QueueStats struct {
+	Buffered bool   `json:"buffered"`
+	Depth    int    `json:"depth"`
+	Capacity int    `json:"capacity"`
+	Writes   uint64 `json:"writes"`
+	// Full is the number of messages which found the queue full, and so waited for space or were spilled to disk.
+	Full uint64 `json:"full"`
+}
+
+// StatsSnapshot is the statistics of all registered loggers and the logging queues at the time it was taken.
+type StatsSnapshot struct {
+	Time    time.Time     `json:"time"`
+	Loggers []LoggerStats `json:"loggers"`
+	Queue   QueueStats    `json:"queue"`
+	// Counters is the value of every named counter, keyed by name.
+	Counters map[string]int64 `json:"counters"`
+}
+
+// Stats returns a snapshot of the statistics of all registered loggers and the logging queues, i.e. to report logging
+// health from a health check endpoint. The same statistics are served by AdminHandler and published by PublishExpvar.
+func Stats() StatsSnapshot {
+	stats := StatsSnapshot{
+		Time:    now(),
+		Loggers: statsOf(registeredLoggers()),
+		Queue: QueueStats{
+			Buffered: buffered(),
+			Depth:    QueueDepth(),
+			Capacity: logQueue.cap(),
+			Full:     logQueue.fullCount(),
+		},
+		Counters: Counters(),
+	}
+	for _, h := range WriteDurations() {
+		stats.Queue.Writes += h.Count
+	}
+	return stats
+}
+
+// ExportJSON writes a snapshot of the logging statistics returned by Stats to w as JSON.
+func ExportJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(Stats())
+}
+
+// statsOf returns the statistics of the provided loggers.
+func statsOf(list []*Logger) []LoggerStats {
+	stats := make([]LoggerStats, 0, len(list))
+	for _, l := range list {
+		stats = append(stats, LoggerStats{
+			ID:        l.id,
+			Category:  l.Category.Name,
+			Level:     l.CurrentLevel().String(),
+			Enabled:   l.IsEnabled(),
+			Count:     l.Count(),
+			Dropped:   l.Dropped(),
+			LastWrite: l.LastWrite(),
+			Tags:      l.Tags(),
+		})
+	}
+	return stats
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"net"
+	"strings"
+	"sync"
+)
+
+var (
+	statsdMu     sync.RWMutex
+	statsdConn   net.Conn
+	statsdMetric string
+	statsdTagged bool
+)
+
+// EnableStatsD emits a StatsD counter increment to the UDP address addr every time a message is written. When tagged
+// is true, the category and level are attached as DogStatsD tags (i.e. "logger.messages:1|c|#category:error,level:error"),
+// otherwise they are appended to the metric name for plain StatsD servers (i.e. "logger.messages.error.error:1|c").
+func EnableStatsD(addr, metric string, tagged bool) error {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	statsdMu.Lock()
+	defer statsdMu.Unlock()
+
+	if statsdConn != nil {
+		statsdConn.Close()
+	}
+	statsdConn = conn
+	statsdMetric = metric
+	statsdTagged = tagged
+	return nil
+}
+
+// DisableStatsD stops emitting StatsD counters.
+func DisableStatsD() {
+	statsdMu.Lock()
+	defer statsdMu.Unlock()
+
+	if statsdConn != nil {
+		statsdConn.Close()
+		statsdConn = nil
+	}
+}
+
+// emitStatsD sends the counter increment for a written entry, if StatsD emission is enabled. Errors are ignored as with
+// any UDP metric client.
+func emitStatsD(e Entry) {
+	statsdMu.RLock()
+	defer statsdMu.RUnlock()
+
+	if statsdConn == nil {
+		return
+	}
+
+	category := statsdSanitise(e.Category)
+	if category == "" {
+		category = "none"
+	}
+	level := strings.ToLower(e.Level.String())
+
+	var packet string
+	if statsdTagged {
+		packet = statsdMetric + ":1|c|#category:" + category + ",level:" + level
+	} else {
+		packet = statsdMetric + "." + category + "." + level + ":1|c"
+	}
+	statsdConn.Write([]byte(packet))
+}
+
+// statsdSanitise lower cases a category and replaces the characters which are reserved by the StatsD line protocol.
+func statsdSanitise(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', ',', '.', ' ':
+			return '_'
+		}
+		return r
+	}, strings.ToLower(s))
+}
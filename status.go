@@ -0,0 +1,222 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// statusMode marks a queued message as an update to its Logger's status line, or as the entry which finalises it.
+type statusMode uint8
+
+const (
+	statusNone statusMode = iota
+	statusUpdate
+	statusDone
+)
+
+// statusBoards are the status lines shown on each terminal, keyed by Writer. statusLines is the number of Writers with
+// status lines, so that writes to other Writers do not need to take the lock.
+var (
+	statusBoards = struct {
+		mu     sync.Mutex
+		boards map[io.Writer]*statusBoard
+	}{
+		boards: make(map[io.Writer]*statusBoard),
+	}
+	statusLines int32
+)
+
+// statusBoard is the block of status lines at the bottom of a terminal, one for each Logger, in the order they were
+// first shown.
+type statusBoard struct {
+	loggers []*Logger
+	lines   map[*Logger]string
+	// shown is the number of lines currently drawn on the terminal, with the cursor at the end of the last.
+	shown int
+}
+
+// Status shows msg on the Logger's status line, which is rewritten in place by each subsequent call rather than being
+// written as a new line, i.e. for progress output such as "downloading 42%". The status line is composed with the
+// Logger's Category and Timestamp and is kept below every other message, which is written above it as normal. Each
+// Logger has its own status line, so the status lines of concurrent Loggers are shown one below the other without
+// overwriting each other. Updates are written in order with other messages by the Writer's queue, and long lines are
+// truncated to the width of the terminal.
+//
+// Status lines are only shown when the Logger's Writer is a terminal, and updates are discarded otherwise. Updates are
+// not counted, hooked, filtered or recorded as entries. The status line is finalised as a normal entry by StatusDone.
+func (l *Logger) Status(msg string) {
+	if !l.IsEnabled() {
+		return
+	}
+	l.stateMu.RLock()
+	writer, timestamp := l.Writer, l.Timestamp
+	l.stateMu.RUnlock()
+	if f, ok := writer.(*os.File); !ok || !isTerminal(f) {
+		return
+	}
+
+	entry := Entry{
+		Time:     now(),
+		Level:    l.CurrentLevel(),
+		Category: l.Category.Name,
+		Message:  l.Message.Compose(strings.ReplaceAll(msg, "\n", " ")),
+	}
+	l.sanitiseEntry(&entry)
+	item := queueItem{
+		logger:    l,
+		writer:    writer,
+		category:  l.Category,
+		timestamp: timestamp,
+		entry:     entry,
+		order:     l.Order,
+		ungrouped: true,
+		status:    statusUpdate,
+	}
+
+	if deterministic {
+		writeSynchronously(item)
+		return
+	}
+	if buffered() {
+		// updates are superseded by the next, so one which does not fit in the queue is discarded rather than spilled
+		logQueue.push(item)
+		return
+	}
+	logQueue.waitConsumed(logQueue.pushWait(item))
+}
+
+// StatusDone finalises the Logger's status line, replacing it with msg logged as a normal entry, i.e. "downloaded
+// 120MB". If the Logger has no status line, msg is logged as normal.
+func (l *Logger) StatusDone(msg string) {
+	if !l.IsEnabled() {
+		return
+	}
+	l.enqueueStatus(msg, false, nil, statusDone)
+}
+
+// updateStatus redraws the status lines of the terminal written to by a status update. Write errors are ignored, as the
+// update is superseded by the next.
+func updateStatus(item queueItem) {
+	composed := getBuffer()
+	composeLine(composed, item, "")
+	line := composed.String()
+	putBuffer(composed)
+	if f, ok := item.writer.(*os.File); ok {
+		// a line as wide as the terminal would leave the cursor on the next line
+		if width := terminalWidth(f); width > 1 {
+			line = truncateWidth(line, width-1)
+		}
+	}
+
+	statusBoards.mu.Lock()
+	defer statusBoards.mu.Unlock()
+	board, ok := statusBoards.boards[item.writer]
+	if !ok {
+		board = &statusBoard{lines: make(map[*Logger]string)}
+		statusBoards.boards[item.writer] = board
+		atomic.AddInt32(&statusLines, 1)
+	}
+	if _, ok := board.lines[item.logger]; !ok {
+		board.loggers = append(board.loggers, item.logger)
+	}
+	board.lines[item.logger] = line
+
+	b := getBuffer()
+	defer putBuffer(b)
+	board.clear(b)
+	board.draw(b)
+	item.writer.Write(b.Bytes())
+}
+
+// writeAroundStatus writes a composed line, ending with a newline, to w in a single Write call, above any status lines
+// shown on w, which are cleared and then redrawn below it. A message which finalises its Logger's status line replaces
+// it.
+func writeAroundStatus(w io.Writer, item queueItem, line []byte) error {
+	if atomic.LoadInt32(&statusLines) == 0 {
+		_, err := w.Write(line)
+		return err
+	}
+	statusBoards.mu.Lock()
+	defer statusBoards.mu.Unlock()
+	board, ok := statusBoards.boards[w]
+	if !ok {
+		_, err := w.Write(line)
+		return err
+	}
+
+	b := getBuffer()
+	defer putBuffer(b)
+	board.clear(b)
+	b.Write(line)
+	if item.status == statusDone {
+		board.remove(item.logger)
+	}
+	board.draw(b)
+	if len(board.loggers) == 0 {
+		delete(statusBoards.boards, w)
+		atomic.AddInt32(&statusLines, -1)
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// hasStatusLines reports whether status lines are shown on w.
+func hasStatusLines(w io.Writer) bool {
+	if atomic.LoadInt32(&statusLines) == 0 {
+		return false
+	}
+	statusBoards.mu.Lock()
+	defer statusBoards.mu.Unlock()
+	_, ok := statusBoards.boards[w]
+	return ok
+}
+
+// clear writes the escape sequences which erase the status lines currently shown, leaving the cursor at the start of
+// the first.
+func (s *statusBoard) clear(b *bytes.Buffer) {
+	if s.shown == 0 {
+		return
+	}
+	b.WriteString("\r\x1b[2K")
+	for i := 1; i < s.shown; i++ {
+		b.WriteString("\x1b[1A\x1b[2K")
+	}
+	s.shown = 0
+}
+
+// draw writes every status line, leaving the cursor at the end of the last.
+func (s *statusBoard) draw(b *bytes.Buffer) {
+	for i, l := range s.loggers {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(s.lines[l])
+	}
+	s.shown = len(s.loggers)
+}
+
+// remove removes the status line of l.
+func (s *statusBoard) remove(l *Logger) {
+	if _, ok := s.lines[l]; !ok {
+		return
+	}
+	delete(s.lines, l)
+	for i, logger := range s.loggers {
+		if logger == l {
+			s.loggers = append(s.loggers[:i], s.loggers[i+1:]...)
+			break
+		}
+	}
+}
+
+// clearStatusLines forgets every status line, without erasing them from the terminal.
+func clearStatusLines() {
+	statusBoards.mu.Lock()
+	statusBoards.boards = make(map[io.Writer]*statusBoard)
+	atomic.StoreInt32(&statusLines, 0)
+	statusBoards.mu.Unlock()
+}
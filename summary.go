@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// statsSummary is the state of the periodic statistics summary.
+var statsSummary struct {
+	mu   sync.Mutex
+	stop func()
+}
+
+// summaryCounts are the counts of a Logger at the previous summary.
+type summaryCounts struct {
+	count   int64
+	dropped int64
+}
+
+// StartStatsSummary logs a "stats" entry with the Internal logger every interval, summarising logging activity for
+// visibility without a metrics stack. The messages, rate (per second) and dropped fields are the activity since the
+// previous summary, followed by the current queue_depth and then count, rate and dropped fields for each Category Name
+// which logged or dropped messages in that time, i.e. "HTTP.count=1200 HTTP.rate=20". Messages logged by the Internal
+// logger are not included. The interval and rates follow the Clock. Any previous summary is stopped.
+func StartStatsSummary(interval time.Duration) {
+	StopStatsSummary()
+	previous := summaryBaseline()
+	last := now()
+	stop := every(interval, func() {
+		current := now()
+		previous = logStatsSummary(previous, current.Sub(last))
+		last = current
+	})
+	statsSummary.mu.Lock()
+	statsSummary.stop = stop
+	statsSummary.mu.Unlock()
+}
+
+// StopStatsSummary stops logging the periodic statistics summary.
+func StopStatsSummary() {
+	statsSummary.mu.Lock()
+	if statsSummary.stop != nil {
+		statsSummary.stop()
+		statsSummary.stop = nil
+	}
+	statsSummary.mu.Unlock()
+}
+
+// summaryBaseline returns the current counts of every registered Logger.
+func summaryBaseline() map[*Logger]summaryCounts {
+	counts := make(map[*Logger]summaryCounts)
+	for _, l := range registeredLoggers() {
+		counts[l] = summaryCounts{count: int64(l.Count()), dropped: l.Dropped()}
+	}
+	return counts
+}
+
+// logStatsSummary logs the activity since the previous counts, which were taken elapsed ago, and returns the current
+// counts. Loggers which share a Category Name are combined, in the order they were registered.
+func logStatsSummary(previous map[*Logger]summaryCounts, elapsed time.Duration) map[*Logger]summaryCounts {
+	current := summaryBaseline()
+
+	var names []string
+	deltas := make(map[string]summaryCounts)
+	var total summaryCounts
+	for _, l := range registeredLoggers() {
+		if l == Internal {
+			continue
+		}
+		// Loggers registered or reset since the previous summary are counted from zero
+		now, before := current[l], previous[l]
+		if now.count < before.count || now.dropped < before.dropped {
+			before = summaryCounts{}
+		}
+		delta := summaryCounts{count: now.count - before.count, dropped: now.dropped - before.dropped}
+		if delta.count <= 0 && delta.dropped <= 0 {
+			continue
+		}
+		name := l.Category.Name
+		if _, ok := deltas[name]; !ok {
+			names = append(names, name)
+		}
+		sum := deltas[name]
+		sum.count += delta.count
+		sum.dropped += delta.dropped
+		deltas[name] = sum
+		total.count += delta.count
+		total.dropped += delta.dropped
+	}
+
+	seconds := elapsed.Seconds()
+	fields := []Field{
+		Int64("messages", total.count),
+		Float64("rate", summaryRate(total.count, seconds)),
+		Int64("dropped", total.dropped),
+		Int("queue_depth", QueueDepth()),
+	}
+	for _, name := range names {
+		d := deltas[name]
+		fields = append(fields, Int64(name+".count", d.count), Float64(name+".rate", summaryRate(d.count, seconds)))
+		if d.dropped > 0 {
+			fields = append(fields, Int64(name+".dropped", d.dropped))
+		}
+	}
+	Internal.LogFields("stats", fields...)
+	return current
+}
+
+// summaryRate returns count per second over the provided number of seconds, to one decimal place.
+func summaryRate(count int64, seconds float64) float64 {
+	if seconds <= 0 {
+		return 0
+	}
+	return math.Round(float64(count)/seconds*10) / 10
+}
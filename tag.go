@@ -0,0 +1,63 @@
+package logger
+
+// Tag adds tags to the Logger, i.e. "noisy", "security" or "network", so that loggers can be controlled with
+// SetEnabledByTag along an axis independent of their Category Names. Tags are not case sensitive, and tags which the
+// Logger already has are ignored.
+func (l *Logger) Tag(tags ...string) {
+	l.stateMu.Lock()
+	l.tags = appendTags(l.tags, tags)
+	l.stateMu.Unlock()
+}
+
+// appendTags returns a copy of existing with the tags which it does not already have appended.
+func appendTags(existing []string, tags []string) []string {
+	updated := append([]string(nil), existing...)
+	for _, tag := range tags {
+		if tag != "" && !containsString(updated, tag) {
+			updated = append(updated, tag)
+		}
+	}
+	return updated
+}
+
+// Untag removes tags from the Logger.
+func (l *Logger) Untag(tags ...string) {
+	l.stateMu.Lock()
+	defer l.stateMu.Unlock()
+	var updated []string
+	for _, tag := range l.tags {
+		if !containsString(tags, tag) {
+			updated = append(updated, tag)
+		}
+	}
+	l.tags = updated
+}
+
+// Tags returns the tags of the Logger, in the order they were added.
+func (l *Logger) Tags() []string {
+	l.stateMu.RLock()
+	defer l.stateMu.RUnlock()
+	return append([]string(nil), l.tags...)
+}
+
+// HasTag reports whether the Logger has the tag, ignoring case.
+func (l *Logger) HasTag(tag string) bool {
+	l.stateMu.RLock()
+	defer l.stateMu.RUnlock()
+	return containsString(l.tags, tag)
+}
+
+// SetEnabledByTag enables or disables all registered loggers which have any of the provided tags, i.e.
+// SetEnabledByTag(false, "noisy"). Loggers without any of the tags are left unchanged.
+func SetEnabledByTag(enabled bool, tags ...string) {
+	loggersMu.RLock()
+	defer loggersMu.RUnlock()
+	for l := range loggers {
+		for _, tag := range tags {
+			if l.HasTag(tag) {
+				l.SetEnabled(enabled)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package logger
+
+import (
+	"os"
+	"strconv"
+)
+
+// terminalWidth returns the width in columns of the terminal f refers to, or 0 if f is not a terminal. The width cannot
+// be queried on this platform, so it is read from the COLUMNS environment variable.
+func terminalWidth(f *os.File) int {
+	if !isTerminal(f) {
+		return 0
+	}
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width < 0 {
+		return 0
+	}
+	return width
+}
+
+// isTerminal reports whether f refers to a terminal, or any other character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,26 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminalWidth returns the width in columns of the terminal f refers to, or 0 if f is not a terminal.
+func terminalWidth(f *os.File) int {
+	var size struct {
+		rows, cols, xpixel, ypixel uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0
+	}
+	return int(size.cols)
+}
+
+// isTerminal reports whether f refers to a terminal.
+func isTerminal(f *os.File) bool {
+	return terminalWidth(f) > 0
+}
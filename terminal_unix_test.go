@@ -0,0 +1,38 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// openTerminal opens the master of a pseudo-terminal with the provided width, skipping the test if there is none.
+func openTerminal(t *testing.T, cols uint16) *os.File {
+	f, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminal: %s", err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	size := struct {
+		rows, cols, xpixel, ypixel uint16
+	}{rows: 24, cols: cols}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCSWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		t.Skipf("cannot set the terminal width: %s", errno)
+	}
+	return f
+}
+
+func TestWrapWidthBatched(t *testing.T) {
+	f := openTerminal(t, 40)
+	if width := wrapWidth(f); width != 40 {
+		t.Fatalf("expected the terminal to be 40 columns wide, got %d", width)
+	}
+	if width := wrapWidth(&writeBatch{writer: f}); width != 40 {
+		t.Fatalf("expected batched messages to be wrapped at the width of the terminal, got %d", width)
+	}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// ElapsedKey is the key of the Field holding the duration logged by TimeTrack and Timer.
+const ElapsedKey = "elapsed"
+
+// TimeTrack starts timing name and returns a function which logs how long has elapsed since, i.e. to log the latency of
+// a function:
+//
+//	defer Info.TimeTrack("handler")()
+//
+// which logs "handler finished elapsed=1.234ms" when the function returns.
+func (l *Logger) TimeTrack(name string) func() {
+	start := now()
+	return func() {
+		l.performLog(name+" finished", false, []Field{Duration(ElapsedKey, now().Sub(start))})
+	}
+}
+
+// Timer is a stopwatch which logs when it is started, at each lap and when it is stopped, along with the elapsed
+// duration. It is created with Logger.Timer.
+type Timer struct {
+	logger  *Logger
+	name    string
+	start   time.Time
+	mu      sync.Mutex
+	lap     time.Time
+	stopped bool
+}
+
+// Timer logs that name has started and returns a Timer which logs its progress.
+func (l *Logger) Timer(name string) *Timer {
+	start := now()
+	l.performLog(name+" started", false, nil)
+	return &Timer{logger: l, name: name, start: start, lap: start}
+}
+
+// Lap logs the duration since the previous lap (or since the Timer was started) and the total elapsed duration, with
+// the provided lap label. The duration since the previous lap is returned.
+func (t *Timer) Lap(label string) time.Duration {
+	current := now()
+	t.mu.Lock()
+	lap := current.Sub(t.lap)
+	t.lap = current
+	t.mu.Unlock()
+
+	t.logger.performLog(t.name+" "+label, false, []Field{Duration("lap", lap), Duration(ElapsedKey, current.Sub(t.start))})
+	return lap
+}
+
+// Stop logs that the Timer has finished along with the total elapsed duration, which is returned. Only the first call
+// to Stop is logged.
+func (t *Timer) Stop() time.Duration {
+	elapsed := now().Sub(t.start)
+	t.mu.Lock()
+	stopped := t.stopped
+	t.stopped = true
+	t.mu.Unlock()
+
+	if !stopped {
+		t.logger.performLog(t.name+" finished", false, []Field{Duration(ElapsedKey, elapsed)})
+	}
+	return elapsed
+}
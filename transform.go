@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TransformFunc is applied to every entry as it is written, after any Logger specific processing. A transform may
+// modify the entry, i.e. to rewrite hostnames, and returns false to drop it. Transforms are called concurrently by the
+// goroutine of each Writer, so must be safe for concurrent use.
+type TransformFunc func(e *Entry) bool
+
+var (
+	transforms   []TransformFunc
+	transformsMu sync.RWMutex
+)
+
+// mutes holds the time until which each muted Category Name is muted. muted is the number of muted categories, so that
+// the lock is not taken while none are muted.
+var (
+	mutes = struct {
+		mu    sync.Mutex
+		until map[string]time.Time
+	}{
+		until: make(map[string]time.Time),
+	}
+	muted int32
+)
+
+// AddTransform registers a transform which is applied to the entries of every Logger, in the order transforms were
+// added. Transforms are called while messages are being written, so they must not log to a Logger themselves. Dropped
+// entries are counted by their Logger's Dropped.
+func AddTransform(t TransformFunc) {
+	transformsMu.Lock()
+	transforms = append(transforms, t)
+	transformsMu.Unlock()
+}
+
+// ClearTransforms removes all registered transforms and muted categories.
+func ClearTransforms() {
+	transformsMu.Lock()
+	transforms = nil
+	transformsMu.Unlock()
+
+	mutes.mu.Lock()
+	mutes.until = make(map[string]time.Time)
+	atomic.StoreInt32(&muted, 0)
+	mutes.mu.Unlock()
+}
+
+// MuteCategory drops all entries with the provided Category Name until d has elapsed, i.e. to temporarily silence a
+// noisy component. Muting a category which is already muted replaces its duration, and a d of 0 or less unmutes it.
+// Dropped entries are counted by their Logger's Dropped.
+func MuteCategory(category string, d time.Duration) {
+	mutes.mu.Lock()
+	defer mutes.mu.Unlock()
+	if d <= 0 {
+		delete(mutes.until, category)
+	} else {
+		mutes.until[category] = now().Add(d)
+	}
+	atomic.StoreInt32(&muted, int32(len(mutes.until)))
+}
+
+// isMuted reports whether a Category Name is muted at the provided time, i.e. when a message was logged rather than
+// when it is written, forgetting the mute once it has expired.
+func isMuted(category string, at time.Time) bool {
+	if atomic.LoadInt32(&muted) == 0 {
+		return false
+	}
+	mutes.mu.Lock()
+	defer mutes.mu.Unlock()
+	until, ok := mutes.until[category]
+	if !ok {
+		return false
+	}
+	if !at.Before(until) {
+		delete(mutes.until, category)
+		atomic.StoreInt32(&muted, int32(len(mutes.until)))
+		return false
+	}
+	return true
+}
+
+// applyTransforms applies the registered transforms to the entry of a queued message, returning the transformed entry
+// and whether the message should be written. The entry is only copied to the heap if transforms are registered.
+func applyTransforms(e Entry) (Entry, bool) {
+	if isMuted(e.Category, e.Time) {
+		return e, false
+	}
+
+	transformsMu.RLock()
+	chain := transforms
+	transformsMu.RUnlock()
+	if len(chain) == 0 {
+		return e, true
+	}
+
+	transformed := e
+	for _, t := range chain {
+		if !t(&transformed) {
+			return transformed, false
+		}
+	}
+	return transformed, true
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMuteCategoryExpires(t *testing.T) {
+	c := NewManualClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	SetClock(c)
+	defer SetClock(nil)
+	defer ClearTransforms()
+
+	var buf bytes.Buffer
+	l := NewLogger(&buf, "NOISY", true)
+	MuteCategory("NOISY", time.Minute)
+	l.Log("muted")
+	c.Add(time.Minute)
+	l.Log("unmuted")
+	Flush(time.Second)
+
+	if strings.Count(buf.String(), "\n") != 1 || !strings.HasSuffix(buf.String(), " unmuted\n") {
+		t.Fatalf("expected only the message logged after the mute expired, got %q", buf.String())
+	}
+	if l.Dropped() != 1 {
+		t.Fatalf("expected the muted message to be counted as dropped, got %d", l.Dropped())
+	}
+}
+
+func TestMuteCategoryDoesNotGrow(t *testing.T) {
+	defer ClearTransforms()
+	for i := 0; i < 100; i++ {
+		MuteCategory("NOISY", time.Minute)
+	}
+
+	transformsMu.RLock()
+	registered := len(transforms)
+	transformsMu.RUnlock()
+	mutes.mu.Lock()
+	categories := len(mutes.until)
+	mutes.mu.Unlock()
+	if registered != 0 || categories != 1 {
+		t.Fatalf("expected a single mute and no transforms, got %d mutes and %d transforms", categories, registered)
+	}
+
+	MuteCategory("NOISY", 0)
+	if isMuted("NOISY", now()) {
+		t.Fatal("expected a duration of 0 to unmute the category")
+	}
+}
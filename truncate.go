@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// SetMaxMessageSize limits the length of the Logger's Message component to n bytes. Longer messages are truncated and
+// suffixed with a "…(+N bytes)" marker stating how many bytes were removed, protecting sinks which impose a line limit
+// (i.e. journald or syslog over UDP). An n of 0 or less removes the limit.
+func (l *Logger) SetMaxMessageSize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	l.stateMu.Lock()
+	l.maxMessageSize = n
+	l.stateMu.Unlock()
+}
+
+// truncateMessage shortens message to at most max bytes, not including the truncation marker. Messages are only cut on
+// a rune boundary so that multi-byte characters are never split.
+func truncateMessage(message string, max int) string {
+	if max <= 0 || len(message) <= max {
+		return message
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + "…(+" + strconv.Itoa(len(message)-cut) + " bytes)"
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// VerbosityPreset is a named set of loggers to enable, defined with DefineVerbosity and applied with Verbosity. Loggers
+// are selected by Category Name or by Level rather than by ID, so that verbosity does not depend on the order in which
+// loggers happened to be created.
+type VerbosityPreset struct {
+	// Categories are the Category Names of loggers to enable, which may be glob patterns as accepted by
+	// SetEnabledByCategory. They are case sensitive.
+	Categories []string
+	// Levels are the Levels of loggers to enable.
+	Levels []Level
+	// All enables every Logger.
+	All bool
+}
+
+// enables reports whether the preset enables the Logger.
+func (p VerbosityPreset) enables(l *Logger) bool {
+	if p.All {
+		return true
+	}
+	for _, c := range p.Categories {
+		if matchCategory(c, l.Category.Name) {
+			return true
+		}
+	}
+	for _, lvl := range p.Levels {
+		if l.CurrentLevel() == lvl {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	verbosityPresets = map[string]VerbosityPreset{
+		"quiet":  {Levels: []Level{LevelError, LevelFatal}},
+		"normal": {Levels: []Level{LevelInfo, LevelWarning, LevelError, LevelFatal}},
+		"debug":  {Levels: []Level{LevelDebug, LevelInfo, LevelWarning, LevelError, LevelFatal}},
+		"trace":  {All: true},
+	}
+	verbosityMu sync.RWMutex
+)
+
+// DefineVerbosity defines (or redefines) a named verbosity preset. The presets "quiet" (errors only), "normal" (info
+// and above), "debug" (debug and above) and "trace" (every logger) are defined by default.
+func DefineVerbosity(name string, preset VerbosityPreset) {
+	verbosityMu.Lock()
+	verbosityPresets[strings.ToLower(name)] = preset
+	verbosityMu.Unlock()
+}
+
+// Verbosity enables every Logger selected by the named preset and disables all others, i.e. Verbosity("debug"). The
+// name is not case sensitive.
+func Verbosity(name string) error {
+	verbosityMu.RLock()
+	preset, ok := verbosityPresets[strings.ToLower(name)]
+	verbosityMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("undefined verbosity preset %q", name)
+	}
+
+	loggersMu.RLock()
+	defer loggersMu.RUnlock()
+	for l := range loggers {
+		l.SetEnabled(preset.enables(l))
+	}
+	return nil
+}
+
+// isVerbosityPreset reports whether a preset has been defined with the provided name.
+func isVerbosityPreset(name string) bool {
+	verbosityMu.RLock()
+	defer verbosityMu.RUnlock()
+	_, ok := verbosityPresets[strings.ToLower(name)]
+	return ok
+}
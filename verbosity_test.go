@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestVerbosityPresetMatchesCategories(t *testing.T) {
+	preset := VerbosityPreset{Categories: []string{"HTTP_*", "DB"}}
+	tests := map[string]bool{
+		"HTTP_IN":  true,
+		"HTTP_OUT": true,
+		"DB":       true,
+		"db":       false,
+		"http_in":  false,
+		"CACHE":    false,
+	}
+	for category, expected := range tests {
+		l := NewLogger(ioutil.Discard, category, true)
+		l.SetLevel(LevelInfo)
+		if enabled := preset.enables(l); enabled != expected {
+			t.Errorf("expected %s to be enabled: %t, got %t", category, expected, enabled)
+		}
+		RemoveLogger(l)
+	}
+}
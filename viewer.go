@@ -0,0 +1,416 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// viewerBacklog is the number of entries buffered for each web viewer client. Entries are dropped for clients which
+// fall further behind, rather than blocking the poller.
+const viewerBacklog = 256
+
+// viewerEntry is the JSON form of an entry streamed to the web viewer.
+type viewerEntry struct {
+	Sequence uint64            `json:"seq"`
+	Time     time.Time         `json:"time"`
+	Level    string            `json:"level"`
+	Category string            `json:"category"`
+	Message  string            `json:"message"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+var viewer = struct {
+	mu          sync.RWMutex
+	subscribers map[chan viewerEntry]bool
+	server      *http.Server
+	history     *RingSink
+	historySize int
+}{
+	subscribers: make(map[chan viewerEntry]bool),
+	historySize: 1000,
+}
+
+// SetViewerHistory sets the number of recent entries retained for the web viewer to search and export (default of
+// 1000). Entries are only retained once a ViewerHandler has been created, and any entries already retained are
+// discarded. An n of 0 or less disables the history.
+func SetViewerHistory(n int) {
+	viewer.mu.Lock()
+	defer viewer.mu.Unlock()
+	viewer.historySize = n
+	if viewer.history == nil {
+		return
+	}
+	viewer.history = nil
+	if n > 0 {
+		viewer.history = NewRingSink(n)
+	}
+}
+
+// ViewerHandler returns an http.Handler serving the web viewer: a page which tails the entries written by every Logger
+// live, with per-category toggles, pause/resume, search and level filtering. Entries are streamed to the page as
+// Server-Sent Events from the /events path. Recent entries are served from the /history path, filtered by the following
+// query parameters:
+//
+//	q         only include entries whose message or fields contain q
+//	regex     if true, q is a regular expression
+//	category  only include entries with one of the provided categories
+//	level     only include entries with at least the provided Level
+//	format    "json" (the default) or "text"
+//	download  if true, the response is sent as a file attachment
+func ViewerHandler() http.Handler {
+	viewer.mu.Lock()
+	if viewer.history == nil && viewer.historySize > 0 {
+		viewer.history = NewRingSink(viewer.historySize)
+	}
+	viewer.mu.Unlock()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, viewerPage)
+	})
+	mux.HandleFunc("/events", serveViewerEvents)
+	mux.HandleFunc("/history", serveViewerHistory)
+	return mux
+}
+
+// StartViewer starts an HTTP server on addr serving the web viewer. The server is shut down by StopPoller or
+// StopViewer.
+func StartViewer(addr string) error {
+	viewer.mu.Lock()
+	if viewer.server != nil {
+		viewer.mu.Unlock()
+		return fmt.Errorf("web viewer is already running on %s", viewer.server.Addr)
+	}
+	server := &http.Server{Addr: addr, Handler: ViewerHandler()}
+	viewer.server = server
+	viewer.mu.Unlock()
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			Internal.Logf("web viewer stopped: %s", err)
+		}
+	}()
+	return nil
+}
+
+// StopViewer shuts down the web viewer server started by StartViewer, if it is running.
+func StopViewer() {
+	viewer.mu.Lock()
+	server := viewer.server
+	viewer.server = nil
+	viewer.mu.Unlock()
+
+	if server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}
+}
+
+// serveViewerEvents streams entries to a web viewer client as Server-Sent Events until it disconnects.
+func serveViewerEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	ch := make(chan viewerEntry, viewerBacklog)
+	viewer.mu.Lock()
+	viewer.subscribers[ch] = true
+	viewer.mu.Unlock()
+	defer func() {
+		viewer.mu.Lock()
+		delete(viewer.subscribers, ch)
+		viewer.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case e := <-ch:
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// serveViewerHistory writes the retained entries which match the request's filters as JSON or text.
+func serveViewerHistory(w http.ResponseWriter, r *http.Request) {
+	filter, err := newViewerFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	viewer.mu.RLock()
+	history := viewer.history
+	viewer.mu.RUnlock()
+	var entries []Entry
+	if history != nil {
+		for _, e := range history.Entries() {
+			if filter.match(e) {
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	text := r.FormValue("format") == "text"
+	if download, _ := strconv.ParseBool(r.FormValue("download")); download {
+		name := "logs.json"
+		if text {
+			name = "logs.txt"
+		}
+		w.Header().Set("Content-Disposition", "attachment; filename="+name)
+	}
+
+	if text {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, e := range entries {
+			w.Write(TextEncoder(e))
+		}
+		return
+	}
+	list := make([]viewerEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, newViewerEntry(e))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// viewerFilter selects the entries returned by the /history path of the web viewer.
+type viewerFilter struct {
+	query      string
+	re         *regexp.Regexp
+	categories []string
+	minLevel   Level
+}
+
+// newViewerFilter creates a viewerFilter from the query parameters of a request.
+func newViewerFilter(r *http.Request) (*viewerFilter, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	f := &viewerFilter{
+		query:      r.Form.Get("q"),
+		categories: r.Form["category"],
+		minLevel:   LevelDebug,
+	}
+	if isRegex, _ := strconv.ParseBool(r.Form.Get("regex")); isRegex && f.query != "" {
+		re, err := regexp.Compile(f.query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %s", err)
+		}
+		f.re = re
+	}
+	if name := r.Form.Get("level"); name != "" {
+		lvl, ok := ParseLevel(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid level: %s", name)
+		}
+		f.minLevel = lvl
+	}
+	return f, nil
+}
+
+// match reports whether an entry passes the filter.
+func (f *viewerFilter) match(e Entry) bool {
+	if e.Level < f.minLevel {
+		return false
+	}
+	if len(f.categories) > 0 && !containsString(f.categories, e.Category) {
+		return false
+	}
+	if f.query == "" {
+		return true
+	}
+	text := e.Message
+	if len(e.Fields) > 0 {
+		text += " " + composeFields(e.Fields)
+	}
+	if f.re != nil {
+		return f.re.MatchString(text)
+	}
+	return strings.Contains(text, f.query)
+}
+
+// publishViewer retains a written entry in the web viewer history and sends it to every connected client.
+func publishViewer(e Entry) {
+	viewer.mu.RLock()
+	defer viewer.mu.RUnlock()
+	if viewer.history != nil {
+		viewer.history.WriteEntry(e)
+	}
+	if len(viewer.subscribers) == 0 {
+		return
+	}
+
+	ve := newViewerEntry(e)
+	for ch := range viewer.subscribers {
+		select {
+		case ch <- ve:
+		default:
+		}
+	}
+}
+
+// newViewerEntry converts an entry into its JSON form.
+func newViewerEntry(e Entry) viewerEntry {
+	ve := viewerEntry{
+		Sequence: e.Sequence,
+		Time:     e.Time,
+		Level:    e.Level.String(),
+		Category: e.Category,
+		Message:  e.Message,
+	}
+	if len(e.Fields) > 0 {
+		ve.Fields = make(map[string]string, len(e.Fields))
+		for _, f := range e.Fields {
+			ve.Fields[f.Key] = f.String()
+		}
+	}
+	return ve
+}
+
+// viewerPage is the web viewer UI.
+const viewerPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>logger</title>
+<style>
+body { margin: 0; font-family: monospace; background: #1e1e1e; color: #ddd; }
+#bar { position: sticky; top: 0; padding: 8px; background: #333; }
+#bar > * { margin-right: 8px; }
+#categories label { margin-right: 12px; }
+#log { padding: 8px; white-space: pre-wrap; }
+a { color: #61afef; }
+.DEBUG { color: #888; } .WARNING { color: #e5c07b; } .ERROR { color: #e06c75; } .FATAL { color: #c678dd; }
+</style>
+</head>
+<body>
+<div id="bar">
+<button id="pause">Pause</button>
+<input id="query" placeholder="search">
+<label><input id="regex" type="checkbox"> regex</label>
+<select id="level">
+<option value="debug">DEBUG+</option>
+<option value="info">INFO+</option>
+<option value="warning">WARNING+</option>
+<option value="error">ERROR+</option>
+<option value="fatal">FATAL</option>
+</select>
+<a id="json" href="#">export JSON</a>
+<a id="text" href="#">export text</a>
+<div id="categories"></div>
+</div>
+<div id="log"></div>
+<script>
+var paused = false, hidden = {}, log = document.getElementById("log");
+var levels = {DEBUG: -1, INFO: 0, WARNING: 1, ERROR: 2, FATAL: 3};
+var query = document.getElementById("query"), regex = document.getElementById("regex"),
+	level = document.getElementById("level");
+
+document.getElementById("pause").onclick = function() {
+	paused = !paused;
+	this.textContent = paused ? "Resume" : "Pause";
+};
+
+// params returns the query string of the current filters.
+function params(format) {
+	var p = new URLSearchParams({q: query.value, regex: regex.checked, level: level.value, format: format});
+	for (var name in hidden) if (!hidden[name]) p.append("category", name);
+	return p.toString();
+}
+
+// matches applies the current filters to a streamed entry.
+function matches(e) {
+	if (levels[e.level] < levels[level.value.toUpperCase()]) return false;
+	if (!query.value) return true;
+	var text = e.message;
+	for (var k in e.fields) text += " " + k + "=" + e.fields[k];
+	if (!regex.checked) return text.indexOf(query.value) >= 0;
+	try { return new RegExp(query.value).test(text); } catch (err) { return false; }
+}
+
+function addCategory(name) {
+	if (name in hidden) return;
+	hidden[name] = false;
+	var label = document.createElement("label"), box = document.createElement("input");
+	box.type = "checkbox";
+	box.checked = true;
+	box.onchange = function() {
+		hidden[name] = !box.checked;
+		document.querySelectorAll("[data-category='" + name + "']").forEach(function(line) {
+			line.style.display = box.checked ? "" : "none";
+		});
+		updateExports();
+	};
+	label.appendChild(box);
+	label.appendChild(document.createTextNode(" " + (name || "(none)")));
+	document.getElementById("categories").appendChild(label);
+}
+
+function appendEntry(e) {
+	var line = document.createElement("div");
+	addCategory(e.category);
+	line.className = e.level;
+	line.dataset.category = e.category;
+	line.style.display = hidden[e.category] ? "none" : "";
+	var text = (e.category ? "[" + e.category + "] " : "") + new Date(e.time).toLocaleString() + " " + e.message;
+	for (var k in e.fields) text += " " + k + "=" + e.fields[k];
+	line.textContent = text;
+	var atBottom = window.innerHeight + window.scrollY >= document.body.scrollHeight - 4;
+	log.appendChild(line);
+	if (atBottom) window.scrollTo(0, document.body.scrollHeight);
+}
+
+function updateExports() {
+	document.getElementById("json").href = "history?download=true&" + params("json");
+	document.getElementById("text").href = "history?download=true&" + params("text");
+}
+
+// reload replaces the displayed entries with the filtered server-side history.
+function reload() {
+	updateExports();
+	var p = new URLSearchParams({q: query.value, regex: regex.checked, level: level.value});
+	fetch("history?" + p.toString()).then(function(resp) { return resp.json(); }).then(function(entries) {
+		log.textContent = "";
+		(entries || []).forEach(appendEntry);
+	});
+}
+
+query.oninput = regex.onchange = level.onchange = reload;
+reload();
+
+new EventSource("events").onmessage = function(msg) {
+	var e = JSON.parse(msg.data);
+	if (paused || !matches(e)) return;
+	appendEntry(e);
+};
+</script>
+</body>
+</html>
+`
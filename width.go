@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges are the ranges of East Asian wide and fullwidth characters and emoji presentation characters, which occupy
+// two columns in a terminal. The ranges are sorted and do not overlap.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x23e9, 0x23ec}, {0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe},
+	{0x2614, 0x2615}, {0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1}, {0x26aa, 0x26ab},
+	{0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce}, {0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3},
+	{0x26f5, 0x26f5}, {0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b}, {0x2728, 0x2728},
+	{0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0},
+	{0x27bf, 0x27bf}, {0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e}, {0x3041, 0x33ff},
+	{0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe30, 0xfe4f},
+	{0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf}, {0x1f18e, 0x1f18e},
+	{0x1f191, 0x1f19a}, {0x1f200, 0x1f251}, {0x1f300, 0x1f64f}, {0x1f680, 0x1f6ff}, {0x1f7e0, 0x1f7eb},
+	{0x1f900, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x3fffd},
+}
+
+// textWidth returns the number of terminal columns occupied by s, where wide characters such as emoji and CJK
+// ideographs occupy two columns, and combining marks, variation selectors, zero width joiners and ANSI escape sequences
+// (i.e. colours added by a Formatter) occupy none.
+func textWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i += escapeLength(s[i:])
+			continue
+		}
+		if s[i] < utf8.RuneSelf {
+			width += runeWidth(rune(s[i]))
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += runeWidth(r)
+		i += size
+	}
+	return width
+}
+
+// escapeLength returns the length in bytes of the ANSI escape sequence at the start of s, which begins with ESC. CSI
+// sequences (i.e. "\x1b[31m") end with a final byte in the range 0x40 to 0x7e, and OSC sequences (i.e. hyperlinks) end
+// with BEL or ESC \. Any other escape is treated as two bytes long.
+func escapeLength(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+// runeWidth returns the number of terminal columns occupied by r.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case r < 0x300:
+		return 1
+	case r == 0x200b || r == 0x200c || r == 0x200d || r == 0x2060 || r == 0xfeff || (r >= 0xfe00 && r <= 0xfe0f):
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return 0
+	}
+
+	// binary search the wide ranges
+	lo, hi := 0, len(wideRanges)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wideRanges[mid][0]:
+			hi = mid
+		case r > wideRanges[mid][1]:
+			lo = mid + 1
+		default:
+			return 2
+		}
+	}
+	return 1
+}
+
+// truncateWidth returns the longest prefix of s which occupies at most width terminal columns. ANSI escape sequences
+// are always kept, so that a colour reset at the end of s still applies.
+func truncateWidth(s string, width int) string {
+	if textWidth(s) <= width {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	used := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			n := escapeLength(s[i:])
+			b = append(b, s[i:i+n]...)
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if w := runeWidth(r); used+w <= width {
+			b = append(b, s[i:i+size]...)
+			used += w
+		} else {
+			// drop the rest of the visible text, but keep any remaining escape sequences
+			used = width + 1
+		}
+		i += size
+	}
+	return string(b)
+}
+
+// writeWrapped writes line to b, soft-wrapping it onto continuation lines indented by indent columns so that the first
+// line occupies at most first columns and each continuation line at most width columns. Lines are broken at the last
+// space which fits, or within a word which is too wide to fit on a line of its own. A width of 0 disables wrapping.
+func writeWrapped(b *bytes.Buffer, line string, first, width, indent int) {
+	limit := first
+	for width > 0 && textWidth(line) > limit {
+		end, next := wrapPoint(line, limit)
+		b.WriteString(line[:end])
+		b.WriteByte('\n')
+		writeSpaces(b, indent)
+		line = line[next:]
+		limit = width
+	}
+	b.WriteString(line)
+}
+
+// wrapPoint returns the offset at which s is broken so that s[:end] occupies at most width columns, and the offset of
+// the text which continues on the next line, skipping the space the line was broken at. At least one character is
+// always kept on the line, so that a character wider than width does not prevent progress.
+func wrapPoint(s string, width int) (end, next int) {
+	used := 0
+	space := -1
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			i += escapeLength(s[i:])
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runeWidth(r)
+		if used+w > width {
+			switch {
+			case r == ' ':
+				return i, i + 1
+			case space > 0:
+				return space, space + 1
+			case used == 0:
+				return i + size, i + size
+			}
+			return i, i
+		}
+		if r == ' ' {
+			space = i
+		}
+		used += w
+		i += size
+	}
+	return len(s), len(s)
+}
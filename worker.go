@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// WorkerKey is the key of the Field added by SetWorkerField.
+const WorkerKey = "worker"
+
+var (
+	workerLabels   = make(map[uint64]string)
+	workerLabelsMu sync.RWMutex
+)
+
+// SetWorkerField enables or disables attaching a "worker" field to each entry, identifying the goroutine which logged
+// it so that interleaved output from concurrent workers can be attributed. The field holds the label set for the
+// goroutine with SetWorkerLabel or Go, or otherwise its goroutine ID.
+func (l *Logger) SetWorkerField(enabled bool) {
+	l.workerField = enabled
+}
+
+// SetWorkerLabel labels the calling goroutine for the "worker" field of entries it logs. ClearWorkerLabel must be called
+// before the goroutine exits to release the label, i.e.
+//
+//	logger.SetWorkerLabel("consumer-" + strconv.Itoa(n))
+//	defer logger.ClearWorkerLabel()
+func SetWorkerLabel(label string) {
+	id := goroutineID()
+	workerLabelsMu.Lock()
+	workerLabels[id] = label
+	workerLabelsMu.Unlock()
+}
+
+// ClearWorkerLabel removes the label of the calling goroutine.
+func ClearWorkerLabel() {
+	id := goroutineID()
+	workerLabelsMu.Lock()
+	delete(workerLabels, id)
+	workerLabelsMu.Unlock()
+}
+
+// Go starts fn in a new goroutine labelled with label, releasing the label once fn returns.
+func Go(label string, fn func()) {
+	go func() {
+		SetWorkerLabel(label)
+		defer ClearWorkerLabel()
+		fn()
+	}()
+}
+
+// currentWorker returns the label of the calling goroutine, or its ID if it has not been labelled.
+func currentWorker() string {
+	id := goroutineID()
+	workerLabelsMu.RLock()
+	label, ok := workerLabels[id]
+	workerLabelsMu.RUnlock()
+	if ok {
+		return label
+	}
+	return strconv.FormatUint(id, 10)
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the header of its stack trace, i.e.
+// "goroutine 18 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
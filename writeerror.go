@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrorPolicy determines how a Logger handles a message which its Writer fails to write.
+type ErrorPolicy int
+
+const (
+	// ErrorFallback writes the message to the Logger's fallback writer. This is the default policy.
+	ErrorFallback ErrorPolicy = iota
+	// ErrorRetry retries the write up to WriteRetries times, with a doubling delay between attempts, before writing the
+	// message to the Logger's fallback writer.
+	ErrorRetry
+	// ErrorDrop discards the message. Discarded messages are counted by Dropped.
+	ErrorDrop
+)
+
+var (
+	// WriteRetries is the number of times a failed write is retried under the ErrorRetry policy.
+	WriteRetries = 3
+	// WriteRetryDelay is the delay before the first retry under the ErrorRetry policy, which doubles after every attempt.
+	WriteRetryDelay = 10 * time.Millisecond
+
+	writeErrorHandler   func(l *Logger, err error)
+	writeErrorHandlerMu sync.RWMutex
+)
+
+// OnWriteError registers a handler which is called whenever a Logger's Writer fails to write a message, after any
+// retries. The handler is called concurrently by the goroutine of each Writer, so must be safe for concurrent use, and
+// must not log to a Logger itself or it may deadlock. A nil handler removes the current handler.
+func OnWriteError(handler func(l *Logger, err error)) {
+	writeErrorHandlerMu.Lock()
+	writeErrorHandler = handler
+	writeErrorHandlerMu.Unlock()
+}
+
+// SetErrorPolicy sets how the Logger handles messages which its Writer fails to write.
+func (l *Logger) SetErrorPolicy(policy ErrorPolicy) {
+	l.errorPolicy = policy
+}
+
+// retryWrite calls write, retrying it if it fails and the Logger uses the ErrorRetry policy. Writes rejected by an open
+// circuit breaker are not retried.
+func (l *Logger) retryWrite(write func() error) error {
+	err := write()
+	if err == nil || err == ErrCircuitOpen || l.errorPolicy != ErrorRetry {
+		return err
+	}
+
+	delay := WriteRetryDelay
+	for i := 0; i < WriteRetries && err != nil; i++ {
+		time.Sleep(delay)
+		delay *= 2
+		if err = write(); err == ErrCircuitOpen {
+			break
+		}
+	}
+	return err
+}
+
+// handleWriteError reports a failed write to the registered handler, then applies the Logger's ErrorPolicy to the
+// message. The composed text of the message is only requested if it is written to the fallback writer.
+func (l *Logger) handleWriteError(err error, writer io.Writer, line func() string) {
+	writeErrorHandlerMu.RLock()
+	handler := writeErrorHandler
+	writeErrorHandlerMu.RUnlock()
+	if handler != nil {
+		handler(l, err)
+	}
+
+	if l.errorPolicy == ErrorDrop {
+		l.drop()
+		return
+	}
+
+	// preserve the message in the fallback writer rather than silently losing it
+	if fallback := l.fallbackWriter(); fallback != writer {
+		writeLine(fallback, line())
+	}
+}
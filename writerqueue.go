@@ -0,0 +1,276 @@
+package logger
+
+import (
+	"io"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// writerIdleTimeout is how long a writer goroutine waits for messages before it exits, so that Writers which are no
+// longer used do not keep a goroutine.
+var writerIdleTimeout = 10 * time.Second
+
+// sharedWriterKey is the writerQueues key of the queue shared by Writers which cannot be used as map keys.
+type sharedWriterKey struct{}
+
+// writerLanes is the queue of a Writer, which has a separate lane for priority messages so that they are written ahead
+// of any backlog of other messages.
+type writerLanes struct {
+	normal   chan queueItem
+	priority chan queueItem
+	// senders is the number of messages being sent to the lanes, which is only increased while writerQueues.mu is held.
+	senders int32
+}
+
+// writerQueues holds a queue and writer goroutine for each distinct Writer, so that a slow Writer, such as a network
+// sink, only delays the messages written to it rather than those of every Logger. The poller dispatches messages to
+// the queues in the order they are received, so messages to the same Writer are still written in order. Each queue
+// holds up to BufferSize messages, after which the poller waits for space. A queue is found and its senders counted
+// while mu is held, but messages are sent without it, so that a writer goroutine can take mu to exit once its queue is
+// empty and has no senders without waiting for the poller.
+var writerQueues struct {
+	mu     sync.Mutex
+	queues map[interface{}]*writerLanes
+}
+
+// dispatch sends a message received by the poller to the queue of its Writer, using the priority lane for messages
+// with a Level of the priority Level or above. Messages to a MultiSink are sent to the queue of each of its Writers by
+// dispatchMulti. Barriers are sent to every queue, and are called once all of them have reached it.
+func dispatch(item queueItem) {
+	if item.barrier == nil {
+		if multi, ok := item.writer.(*MultiSink); ok && item.fanout == nil {
+			dispatchMulti(multi, item)
+			return
+		}
+		writerQueues.mu.Lock()
+		lanes := writerQueue(item.writer)
+		atomic.AddInt32(&lanes.senders, 1)
+		writerQueues.mu.Unlock()
+
+		if isPriority(item) {
+			lanes.priority <- item
+		} else {
+			lanes.normal <- item
+		}
+		atomic.AddInt32(&lanes.senders, -1)
+		return
+	}
+
+	writerQueues.mu.Lock()
+	all := make([]*writerLanes, 0, len(writerQueues.queues))
+	for _, lanes := range writerQueues.queues {
+		atomic.AddInt32(&lanes.senders, 1)
+		all = append(all, lanes)
+	}
+	writerQueues.mu.Unlock()
+
+	if len(all) == 0 {
+		go item.barrier()
+		return
+	}
+	// only the barrier is captured, so that other messages are not moved to the heap
+	barrier := item.barrier
+	remaining := int32(len(all))
+	for _, lanes := range all {
+		lanes.normal <- queueItem{barrier: func() {
+			if atomic.AddInt32(&remaining, -1) == 0 {
+				barrier()
+			}
+		}}
+		atomic.AddInt32(&lanes.senders, -1)
+	}
+}
+
+// dispatchMulti sends a copy of a message to the queue of each Writer of a MultiSink which receives its Level, so that
+// a slow Writer, such as a network sink added with AddWriter, does not delay writes to the others. The message is
+// transformed once here rather than by each copy. A message which none of the Writers receive is sent to the queue of
+// the MultiSink itself, so that it is still finished, i.e. its post-write hooks are called.
+func dispatchMulti(multi *MultiSink, item queueItem) {
+	entry, ok := applyTransforms(item.entry)
+	if !ok {
+		item.logger.drop()
+		return
+	}
+	item.entry = entry
+
+	multi.mu.RLock()
+	targets := multi.targets
+	multi.mu.RUnlock()
+	receivers := 0
+	for _, t := range targets {
+		if entry.Level >= t.minLevel {
+			receivers++
+		}
+	}
+
+	if receivers == 0 {
+		item.fanout = &fanout{remaining: 1}
+		dispatch(item)
+		return
+	}
+	item.fanout = &fanout{remaining: receivers}
+	for _, t := range targets {
+		if entry.Level >= t.minLevel {
+			target := item
+			target.writer, target.encoder = t.writer, t.encoder
+			dispatch(target)
+		}
+	}
+}
+
+// fanout tracks the copies of a message dispatched to the Writers of a MultiSink.
+type fanout struct {
+	mu        sync.Mutex
+	remaining int
+	errs      multiError
+}
+
+// done records that a copy has been written, reporting whether it was the last along with the combined errors of every
+// copy.
+func (f *fanout) done(err error) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err != nil {
+		f.errs = append(f.errs, err)
+	}
+	f.remaining--
+	if f.remaining > 0 || len(f.errs) == 0 {
+		return f.remaining == 0, nil
+	}
+	return true, f.errs
+}
+
+// writerQueue returns the queue of a Writer, starting its writer goroutine if it does not exist yet. writerQueues.mu
+// must be held.
+func writerQueue(w io.Writer) *writerLanes {
+	var key interface{} = w
+	if w != nil && !reflect.TypeOf(w).Comparable() {
+		key = sharedWriterKey{}
+	}
+	if lanes, ok := writerQueues.queues[key]; ok {
+		return lanes
+	}
+
+	if writerQueues.queues == nil {
+		writerQueues.queues = make(map[interface{}]*writerLanes)
+	}
+	lanes := &writerLanes{
+		normal:   make(chan queueItem, BufferSize),
+		priority: make(chan queueItem, BufferSize),
+	}
+	writerQueues.queues[key] = lanes
+	go writeQueue(key, lanes)
+	return lanes
+}
+
+// writeQueue writes the messages of a Writer's queue, preferring its priority lane, until the queue is closed or has
+// been idle for writerIdleTimeout. Messages are coalesced into batches if write batching is enabled.
+func writeQueue(key interface{}, lanes *writerLanes) {
+	// category grouping is tracked per Writer, as it describes what has been written to that Writer
+	var previousCategory string
+	var batch *writeBatch
+	defer func() {
+		if batch != nil {
+			batch.flush()
+		}
+	}()
+	// Writers sharing a queue cannot be compared to find the Writer of a batch, so their messages are not batched
+	_, shared := key.(sharedWriterKey)
+	idleTimeout := writerIdleTimeout
+	idle := time.NewTimer(idleTimeout)
+	defer idle.Stop()
+
+	write := func(item queueItem) {
+		// the queue is only idle once nothing has been written for idleTimeout, including by writePriority
+		defer idle.Reset(idleTimeout)
+		if item.barrier != nil {
+			// batched messages must be written before a barrier is called
+			if batch != nil {
+				batch.flush()
+			}
+			performWrite(item, &previousCategory)
+			return
+		}
+
+		// status lines are redrawn around each message, so messages are not batched while they are shown
+		if size, _ := batchingEnabled(); size > 0 && !shared && batchable(item.writer) && item.status != statusUpdate &&
+			!hasStatusLines(item.writer) {
+			if batch == nil {
+				batch = &writeBatch{writer: item.writer}
+			}
+			batch.logger = item.logger
+			item.writer = batch
+		} else if batch != nil {
+			batch.flush()
+		}
+		performWrite(item, &previousCategory)
+	}
+	writePriority := func() {
+		for {
+			select {
+			case item := <-lanes.priority:
+				write(item)
+			default:
+				return
+			}
+		}
+	}
+
+	for {
+		writePriority()
+		select {
+		case item := <-lanes.priority:
+			write(item)
+
+		case item, ok := <-lanes.normal:
+			if !ok {
+				writePriority()
+				return
+			}
+			// priority messages queued before a barrier must be written before it is called
+			if item.barrier != nil {
+				writePriority()
+			}
+			write(item)
+
+		case <-batch.expired():
+			batch.flush()
+			idle.Reset(idleTimeout)
+
+		case <-idle.C:
+			writerQueues.mu.Lock()
+			if len(lanes.normal) == 0 && len(lanes.priority) == 0 && atomic.LoadInt32(&lanes.senders) == 0 &&
+				writerQueues.queues[key] == lanes {
+				delete(writerQueues.queues, key)
+				writerQueues.mu.Unlock()
+				return
+			}
+			writerQueues.mu.Unlock()
+			idle.Reset(idleTimeout)
+		}
+	}
+}
+
+// stopWriterQueues stops every writer goroutine once its queue has been written. It is called by the poller when it
+// stops.
+func stopWriterQueues() {
+	writerQueues.mu.Lock()
+	for _, lanes := range writerQueues.queues {
+		close(lanes.normal)
+	}
+	writerQueues.queues = nil
+	writerQueues.mu.Unlock()
+}
+
+// writerQueueDepth returns the number of messages waiting in the writer queues.
+func writerQueueDepth() int {
+	writerQueues.mu.Lock()
+	defer writerQueues.mu.Unlock()
+	depth := 0
+	for _, lanes := range writerQueues.queues {
+		depth += len(lanes.normal) + len(lanes.priority)
+	}
+	return depth
+}
@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowWriter blocks every write until it is opened, then takes delay to write each message containing "slow".
+type slowWriter struct {
+	gate  chan struct{}
+	delay time.Duration
+	mu    sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	if bytes.Contains(p, []byte("slow")) {
+		time.Sleep(w.delay)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestWriterQueueIdleWhileDispatchBlocked(t *testing.T) {
+	defer func(timeout time.Duration) { writerIdleTimeout = timeout }(writerIdleTimeout)
+	writerIdleTimeout = 20 * time.Millisecond
+
+	w := &slowWriter{gate: make(chan struct{}), delay: 2 * time.Millisecond}
+	priority := NewLogger(w, "PRIORITY", true)
+	priority.SetLevel(LevelError)
+	normal := NewLogger(w, "NORMAL", true)
+	normal.SetLevel(LevelInfo)
+	defer RemoveLogger(priority)
+	defer RemoveLogger(normal)
+
+	// the priority messages take longer than the idle timeout to write while the poller waits for space in the full
+	// normal lane
+	const priorityCount, normalCount = 30, 1025
+	go func() {
+		for i := 0; i < priorityCount; i++ {
+			priority.Log("slow")
+		}
+		for i := 0; i < normalCount; i++ {
+			normal.Log("fast")
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(w.gate)
+
+	if !Flush(10 * time.Second) {
+		t.Fatal("flush timed out")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if lines := strings.Count(w.buf.String(), "\n"); lines != priorityCount+normalCount {
+		t.Fatalf("expected %d messages to be written, got %d", priorityCount+normalCount, lines)
+	}
+}
+
+// funcWriter cannot be used as a map key, so every funcWriter shares a single writer queue.
+type funcWriter struct {
+	write func(p []byte)
+}
+
+func (w funcWriter) Write(p []byte) (int, error) {
+	w.write(p)
+	return len(p), nil
+}
+
+func TestSharedQueueWriters(t *testing.T) {
+	SetWriteBatching(4096, time.Minute)
+	defer SetWriteBatching(0, 0)
+
+	var mu sync.Mutex
+	outputs := make(map[string]*bytes.Buffer)
+	writer := func(name string) funcWriter {
+		outputs[name] = &bytes.Buffer{}
+		return funcWriter{write: func(p []byte) {
+			mu.Lock()
+			outputs[name].Write(p)
+			mu.Unlock()
+		}}
+	}
+	first := NewLogger(writer("first"), "FIRST", true)
+	second := NewLogger(writer("second"), "SECOND", true)
+	defer RemoveLogger(first)
+	defer RemoveLogger(second)
+
+	for i := 0; i < 10; i++ {
+		first.Log("first")
+		second.Log("second")
+	}
+	if !Flush(time.Second) {
+		t.Fatal("flush timed out")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for name, buf := range outputs {
+		if lines := strings.Count(buf.String(), " "+name+"\n"); lines != 10 || strings.Count(buf.String(), "\n") != 10 {
+			t.Errorf("expected only the 10 messages logged for %s, got %q", name, buf.String())
+		}
+	}
+}
+
+func TestAddWriterQueuesEachWriter(t *testing.T) {
+	var mu sync.Mutex
+	var fast bytes.Buffer
+	slow := &slowWriter{gate: make(chan struct{})}
+	l := NewLogger(slow, "FANOUT", true)
+	l.AddWriter(funcWriter{write: func(p []byte) {
+		mu.Lock()
+		fast.Write(p)
+		mu.Unlock()
+	}}, LevelDebug)
+	defer RemoveLogger(l)
+
+	var hooks int32
+	l.AddPostWriteHook(func(l *Logger, e Entry, err error) {
+		atomic.AddInt32(&hooks, 1)
+	})
+
+	// the added Writer is written to while the Logger's original Writer is blocked
+	l.Log("not delayed")
+	written := false
+	for deadline := time.Now().Add(time.Second); !written && time.Now().Before(deadline); {
+		mu.Lock()
+		written = strings.Contains(fast.String(), "not delayed")
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	if !written {
+		t.Fatal("the message was not written while another Writer of the Logger was blocked")
+	}
+	if n := atomic.LoadInt32(&hooks); n != 0 {
+		t.Errorf("post-write hooks were called before every Writer was written to")
+	}
+
+	close(slow.gate)
+	if !Flush(time.Second) {
+		t.Fatal("flush timed out")
+	}
+	slow.mu.Lock()
+	defer slow.mu.Unlock()
+	if !strings.Contains(slow.buf.String(), "not delayed") {
+		t.Errorf("expected the message to be written to the original Writer, got %q", slow.buf.String())
+	}
+	if n := atomic.LoadInt32(&hooks); n != 1 {
+		t.Errorf("expected the post-write hooks to be called once, got %d", n)
+	}
+}